@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Location struct {
+	Section string
+	Name    string
+	Anchor  string
+}
+
+func (l Location) String() string {
+	var s strings.Builder
+	if l.Section != "" {
+		s.WriteString("[" + l.Section + "]")
+	}
+
+	if l.Name != "" {
+		if s.Len() > 0 {
+			s.WriteString(" ")
+		}
+
+		s.WriteString(l.Name)
+	}
+
+	if l.Anchor != "" {
+		s.WriteString(" (" + TelegramBotsApiUrl + "#" + l.Anchor + ")")
+	}
+
+	return s.String()
+}
+
+type Diagnostic struct {
+	Location Location
+	Row      string
+	Message  string
+}
+
+func (d *Diagnostic) String() string {
+	s := d.Location.String() + ": " + d.Message
+	if d.Row != "" {
+		s += fmt.Sprintf(": %q", d.Row)
+	}
+
+	return s
+}
+
+type Diagnostics []*Diagnostic
+
+func (d *Diagnostics) Add(location Location, row, message string) {
+	*d = append(*d, &Diagnostic{
+		Location: location,
+		Row:      row,
+		Message:  message,
+	})
+}
+
+func (d Diagnostics) Err() error {
+	if len(d) == 0 {
+		return nil
+	}
+
+	return d
+}
+
+func (d Diagnostics) Error() string {
+	lines := make([]string, 0, len(d)+1)
+	lines = append(lines, fmt.Sprintf("%d problem(s) found while reading API description:", len(d)))
+	for _, diagnostic := range d {
+		lines = append(lines, "  "+diagnostic.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -2,13 +2,25 @@ package main
 
 //go:generate go run .
 //go:generate gofmt -w api/types.go
+//go:generate gofmt -w api/types_test.go
+//go:generate gofmt -w api/version.go
+//go:generate gofmt -w api/updates.go
+//go:generate gofmt -w api/updates_test.go
 //go:generate gofmt -w api/requests
+//go:generate gofmt -w api/webhook
 
 import (
+	"flag"
+	"fmt"
+	"go/token"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -20,17 +32,55 @@ import (
 
 const ApiDir = "api"
 const RequestsDir = "requests"
+const WebhookDir = "webhook"
 const TemplatesDir = "templates"
 const TypesHeaderTemplate = "types_header.tmpl"
+const TypesTestTemplate = "types_test.tmpl"
+const ShapeTemplate = "shape.tmpl"
 const TypesTemplate = "types.tmpl"
+const UnionTemplate = "union.tmpl"
+const EnumTemplate = "enum.tmpl"
+const InterfaceTemplate = "interface.tmpl"
 const RequestFileTemplate = "request.tmpl"
 const RequestTestTemplate = "request_test.tmpl"
 const HelpersTestTemplate = "helpers_test.tmpl"
+const ClientTemplate = "client.tmpl"
+const ClientTestTemplate = "client_test.tmpl"
+const RegistryTemplate = "registry.tmpl"
+const RegistryTestTemplate = "registry_test.tmpl"
+const UpdatesTemplate = "updates.tmpl"
+const UpdatesTestTemplate = "updates_test.tmpl"
+const PollerTemplate = "poller.tmpl"
+const PollerTestTemplate = "poller_test.tmpl"
+const WebhookTemplate = "webhook.tmpl"
+const WebhookTestTemplate = "webhook_test.tmpl"
+const VersionTemplate = "version.tmpl"
+const ChangelogTemplate = "changelog.tmpl"
+const CommentWidth = 100
 const TypesFile = "types.go"
+const TypesTestFile = "types_test.go"
+const VersionFile = "version.go"
+const ChangelogFile = "CHANGELOG.md"
+const ClientFile = "client.go"
+const ClientTestFile = "client_test.go"
+const RegistryFile = "registry.go"
+const RegistryTestFile = "registry_test.go"
+const UpdatesFile = "updates.go"
+const UpdatesTestFile = "updates_test.go"
+const UpdateType = "Update"
+const AllowedUpdatesField = "allowed_updates"
+const GetUpdatesMethod = "getUpdates"
+const DefaultPollTimeout = 30
+
+type TypesHeaderTemplateData struct {
+	Imports []string
+}
 
 type TypeTemplateData struct {
-	Type   *Type
-	Fields []*TypeFieldTemplateData
+	Type              *Type
+	Fields            []*TypeFieldTemplateData
+	ConstFields       []*TypeFieldTemplateData
+	PolymorphicFields []*TypeFieldTemplateData
 }
 
 func (d *TypeTemplateData) SortFields() {
@@ -50,11 +100,71 @@ func (d *TypeTemplateData) SortFields() {
 }
 
 type TypeFieldTemplateData struct {
-	Field *Field
-	Name  string
+	Field     *Field
+	Name      string
+	Type      string
+	Interface string
+	IsArray   bool
+}
+
+type InterfaceTemplateData struct {
+	Type          *Type
+	Name          string
+	Method        string
+	Subtypes      []string
+	Discriminator string
+	Variants      []InterfaceVariantTemplateData
+	Shapes        []InterfaceShapeTemplateData
+	Samples       []InterfaceSampleTemplateData
+}
+
+type InterfaceShapeTemplateData struct {
+	Type     string
+	Required []string
+	Known    []string
+	Consts   map[string]string
+}
+
+type InterfaceSampleTemplateData struct {
+	Type string
+	Json string
+}
+
+type InterfaceVariantTemplateData struct {
+	Value string
 	Type  string
 }
 
+type Enums map[*Field]string
+
+type EnumTemplateData struct {
+	Name        string
+	Description string
+	Values      []EnumValueTemplateData
+}
+
+type EnumValueTemplateData struct {
+	Name  string
+	Value string
+}
+
+type UnionTemplateData struct {
+	Name     string
+	Variants []UnionVariantTemplateData
+}
+
+type UnionVariantTemplateData struct {
+	Name    string
+	Type    string
+	IsArray bool
+	Json    string
+}
+
+type TypesTestTemplateData struct {
+	Interfaces []InterfaceTemplateData
+	Unions     []UnionTemplateData
+}
+
 type RequestTemplateData struct {
 	Imports              []string
 	Method               *Method
@@ -62,7 +172,95 @@ type RequestTemplateData struct {
 	Fields               []RequestFieldTemplateData
 	Files                Files
 	ResponseType         string
+	ResultType           string
 	ResponseTypeVariants []string
+	ResponseDecoder      string
+	ResponseItemType     string
+	IsResponseArray      bool
+	IsEmptyValid         bool
+	Pagination           *PaginationTemplateData
+	ValidateTests        ValidateTestsTemplateData
+	DecodedFields        []RequestDecodedFieldTemplateData
+	JsonTests            JsonTestsTemplateData
+	DoTest               *DoTestTemplateData
+	ConstructorTests     ConstructorTestsTemplateData
+}
+
+type ConstructorTestsTemplateData struct {
+	Required       []ConstructorTestFieldTemplateData
+	Optional       []ConstructorTestFieldTemplateData
+	IsTelegramUsed bool
+}
+
+type ConstructorTestFieldTemplateData struct {
+	Name      string
+	Param     string
+	Value     string
+	IsPointer bool
+}
+
+type DoTestTemplateData struct {
+	Json           string
+	Want           string
+	Item           string
+	IsTelegramUsed bool
+}
+
+type JsonTestsTemplateData struct {
+	Fields         []JsonTestFieldTemplateData
+	Omitted        []string
+	IsTelegramUsed bool
+}
+
+type JsonTestFieldTemplateData struct {
+	Name  string
+	Key   string
+	Value string
+	Json  string
+}
+
+type RequestDecodedFieldTemplateData struct {
+	Name     string
+	Key      string
+	Decoder  string
+	IsArray  bool
+	Variants []RequestDecodedVariantTemplateData
+}
+
+type RequestDecodedVariantTemplateData struct {
+	Type    string
+	ApiType string
+	Kind    string
+	Shape   *InterfaceShapeTemplateData
+}
+
+type ValidateTestsTemplateData struct {
+	Cases          []ValidateCaseTemplateData
+	IsTelegramUsed bool
+}
+
+type ValidateCaseTemplateData struct {
+	Name   string
+	Fields []ValidateCaseFieldTemplateData
+	Error  string
+}
+
+type ValidateCaseFieldTemplateData struct {
+	Name  string
+	Value string
+}
+
+type PaginationTemplateData struct {
+	Offset               string
+	Limit                string
+	DefaultLimit         int64
+	Items                string
+	ItemType             string
+	Result               string
+	NextOffset           string
+	IsLimitRequired      bool
+	IsNextOffsetRequired bool
+	IsTelegramUsed       bool
 }
 
 func (d *RequestTemplateData) SortFields() {
@@ -81,6 +279,45 @@ func (d *RequestTemplateData) SortFields() {
 	})
 }
 
+type UpdatesTemplateData struct {
+	Fields    []UpdateFieldTemplateData
+	Handled   *UpdateFieldTemplateData
+	Unhandled *UpdateFieldTemplateData
+}
+
+type UpdateFieldTemplateData struct {
+	Field      *Field
+	Name       string
+	Const      string
+	Param      string
+	Type       string
+	Value      string
+	IsExplicit bool
+}
+
+type PollerTemplateData struct {
+	Name           string
+	Offset         string
+	Timeout        string
+	UpdateId       string
+	DefaultTimeout int
+}
+
+type RequestsTemplateData struct {
+	Requests    []RequestTemplateData
+	DecodeTests []RegistryDecodeTestTemplateData
+}
+
+type RegistryDecodeTestTemplateData struct {
+	Name    string
+	Method  string
+	Payload string
+	Field   string
+	Key     string
+	Type    string
+	IsItem  bool
+}
+
 type RequestFieldTemplateData struct {
 	Field       *Field
 	Name        string
@@ -89,7 +326,13 @@ type RequestFieldTemplateData struct {
 	IsObject    bool
 	IsInputFile bool
 	IsChatId    bool
+	IsInterface bool
+	Enum        string
 	Variants    [][]RequestFieldTemplateData
+	Param       string
+	ParamType   string
+	ParseMode   string
+	Entities    string
 }
 
 type Files struct {
@@ -100,6 +343,10 @@ type Files struct {
 	Variants     map[string][]FileVariants
 }
 
+func (f Files) HasFiles() bool {
+	return len(f.DirectFields) > 0 || len(f.Fields) > 0 || len(f.Arrays) > 0 || len(f.Subtypes) > 0 || len(f.Variants) > 0
+}
+
 type FileSubtype struct {
 	Type   string
 	Fields []FileField
@@ -116,234 +363,1217 @@ type FileField struct {
 	IsRequired bool
 }
 
+var templateFuncs = template.FuncMap{
+	"comment":  getComment,
+	"decoder":  getInterfaceDecoderName,
+	"link":     getDocumentationLink,
+	"markdown": getChangeItemMarkdown,
+}
+
 func main() {
+	var input, snapshot, specInput, specOutput, openApiOutput, schemasOutput string
+	var generate bool
+	flag.StringVar(&input, "input", "", "read documentation from saved HTML file instead of fetching it (\"-\" for stdin)")
+	flag.StringVar(&snapshot, "snapshot", "", "save fetched documentation HTML to file")
+	flag.StringVar(&specInput, "spec", "", "read parsed API spec from JSON file instead of documentation (\"-\" for stdin)")
+	flag.StringVar(&specOutput, "spec-out", "", "save parsed API spec to JSON file (\"-\" for stdout)")
+	flag.StringVar(&openApiOutput, "openapi-out", "", "save OpenAPI 3.1 document to JSON file (\"-\" for stdout)")
+	flag.StringVar(&schemasOutput, "schemas-out", "", "save JSON Schema of every type to directory")
+	flag.BoolVar(&generate, "generate", true, "generate API library code")
+	flag.Parse()
+
+	if input != "" && snapshot != "" {
+		log.Fatalln("flags -input and -snapshot are mutually exclusive")
+	}
+
+	if specInput != "" && (input != "" || snapshot != "") {
+		log.Fatalln("flag -spec can not be combined with -input or -snapshot")
+	}
+
 	var err error
 
-	var doc *html.Node
-	if doc, err = fetch(); err != nil {
+	var spec *Spec
+	if specInput != "" {
+		spec, err = readSpec(specInput)
+	} else {
+		spec, err = parseSpec(input, snapshot)
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	if specOutput != "" {
+		if err = writeSpec(specOutput, spec); err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	if openApiOutput != "" {
+		if err = writeJson(openApiOutput, buildOpenApi(spec)); err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	if schemasOutput != "" {
+		if err = writeJsonSchemas(schemasOutput, spec.Types); err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	if !generate {
+		return
+	}
+
+	if err = generateVersion(spec.Version); err != nil {
 		log.Fatalln(err)
 	}
 
-	var methods Methods
-	var types Types
-	if methods, types, err = parse(doc); err != nil {
+	if err = generateChangelog(spec.Changes); err != nil {
 		log.Fatalln(err)
 	}
 
-	if err = generateTypes(types); err != nil {
+	if err = generateTypes(spec.Types, spec.Methods); err != nil {
 		log.Fatalln(err)
 	}
 
-	if err = generateRequests(types, methods); err != nil {
+	if err = generateUpdates(spec.Types, spec.Methods); err != nil {
+		log.Fatalln(err)
+	}
+
+	if err = generateRequests(spec.Types, spec.Methods); err != nil {
+		log.Fatalln(err)
+	}
+
+	if err = generateWebhook(spec.Types); err != nil {
 		log.Fatalln(err)
 	}
 }
 
-func generateTypes(types Types) (err error) {
-	var file *os.File
-	if file, err = os.Create(filepath.Join(ApiDir, TypesFile)); err != nil {
+func parseSpec(input, snapshot string) (spec *Spec, err error) {
+	var doc *html.Node
+	if input != "" {
+		doc, err = load(input)
+	} else {
+		doc, err = fetch(snapshot)
+	}
+	if err != nil {
 		return
 	}
-	//goland:noinspection GoUnhandledErrorResult
-	defer file.Close()
 
-	var tmpl *template.Template
-	if tmpl, err = template.ParseFiles(filepath.Join(TemplatesDir, TypesHeaderTemplate), filepath.Join(TemplatesDir, TypesTemplate)); err != nil {
+	diags := make(Diagnostics, 0)
+
+	spec = new(Spec)
+	if spec.Changes, err = parseChanges(doc, &diags); err != nil {
 		return
 	}
 
-	if err = tmpl.ExecuteTemplate(file, TypesHeaderTemplate, nil); err != nil {
+	if spec.Methods, spec.Types, err = parse(doc, &diags); err != nil {
 		return
 	}
 
-	for _, key := range types.GetFilteredKeys() {
-		item := types[key]
+	spec.Version = getLatestVersion(spec.Changes, &diags)
 
-		fields := make([]*TypeFieldTemplateData, 0, len(item.Fields))
-		for _, field := range item.Fields {
-			fields = append(fields, &TypeFieldTemplateData{
-				Field: field,
-				Name:  strcase.ToCamel(field.Key),
-				Type:  getGoType(types, field.Type, field.IsRequired, ""),
-			})
-		}
+	if err = diags.Err(); err != nil {
+		return
+	}
 
-		data := TypeTemplateData{
-			Type:   item,
-			Fields: fields,
-		}
-		data.SortFields()
+	return
+}
 
-		if err = tmpl.ExecuteTemplate(file, TypesTemplate, data); err != nil {
-			return
-		}
+func parseTemplates(names ...string) (*template.Template, error) {
+	paths := make([]string, 0, len(names))
+	for _, name := range names {
+		paths = append(paths, filepath.Join(TemplatesDir, name))
 	}
 
-	return
+	return template.New(names[0]).Funcs(templateFuncs).ParseFiles(paths...)
 }
 
-func generateRequests(types Types, methods Methods) (err error) {
-	requestsDirPath := filepath.Join(ApiDir, RequestsDir)
-	if err = os.RemoveAll(requestsDirPath); err != nil {
+func generateVersion(version *Version) (err error) {
+	if version == nil {
 		return
 	}
 
-	if err = os.Mkdir(requestsDirPath, 0o755); err != nil {
+	var file *os.File
+	if file, err = os.Create(filepath.Join(ApiDir, VersionFile)); err != nil {
 		return
 	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer file.Close()
 
-	var helpersTmpl *template.Template
-	if helpersTmpl, err = template.ParseFiles(filepath.Join(TemplatesDir, HelpersTestTemplate)); err != nil {
+	var tmpl *template.Template
+	if tmpl, err = parseTemplates(VersionTemplate); err != nil {
 		return
 	}
 
-	var helpersFile *os.File
-	if helpersFile, err = os.Create(filepath.Join(requestsDirPath, "helpers_test.go")); err != nil {
+	if err = tmpl.ExecuteTemplate(file, VersionTemplate, version); err != nil {
 		return
 	}
-	//goland:noinspection GoUnhandledErrorResult
-	defer helpersFile.Close()
-	if err = helpersTmpl.ExecuteTemplate(helpersFile, HelpersTestTemplate, nil); err != nil {
+
+	return
+}
+
+func generateChangelog(changes []*Change) (err error) {
+	if len(changes) == 0 {
 		return
 	}
 
-	var reqTmpl, testTmpl *template.Template
-	if reqTmpl, err = template.ParseFiles(filepath.Join(TemplatesDir, RequestFileTemplate)); err != nil {
+	var file *os.File
+	if file, err = os.Create(filepath.Join(ApiDir, ChangelogFile)); err != nil {
 		return
 	}
-	if testTmpl, err = template.ParseFiles(filepath.Join(TemplatesDir, RequestTestTemplate)); err != nil {
+	//goland:noinspection GoUnhandledErrorResult
+	defer file.Close()
+
+	var tmpl *template.Template
+	if tmpl, err = parseTemplates(ChangelogTemplate); err != nil {
 		return
 	}
 
-	for _, item := range methods {
-		if err = generateRequestFile(reqTmpl, types, item); err != nil {
-			return
+	if err = tmpl.ExecuteTemplate(file, ChangelogTemplate, changes); err != nil {
+		return
+	}
+
+	return
+}
+
+func getComment(text string) string {
+	lines := make([]string, 0)
+	for i, paragraph := range strings.Split(text, "\n\n") {
+		if i > 0 {
+			lines = append(lines, "//")
 		}
 
-		if err = generateRequestTestFile(testTmpl, types, item); err != nil {
-			return
+		var line string
+		for _, word := range strings.Fields(paragraph) {
+			if line != "" && len(line)+len(word)+1 > CommentWidth {
+				lines = append(lines, "// "+line)
+				line = ""
+			}
+
+			if line != "" {
+				line += " "
+			}
+			line += word
+		}
+
+		if line != "" {
+			lines = append(lines, "// "+line)
 		}
 	}
 
-	return
+	return strings.Join(lines, "\n")
 }
 
-func generateRequestFile(tmpl *template.Template, types Types, method *Method) (err error) {
-	var file *os.File
-	if file, err = os.Create(filepath.Join(ApiDir, RequestsDir, strcase.ToSnake(method.Key)+".go")); err != nil {
-		return
-	}
-	//goland:noinspection GoUnhandledErrorResult
-	defer file.Close()
+func getDocumentationLink(anchor string) string {
+	return TelegramBotsApiUrl + "#" + anchor
+}
 
-	data := buildRequestTemplateData(types, method)
-	if err = tmpl.ExecuteTemplate(file, RequestFileTemplate, &data); err != nil {
-		return
+func getChangeItemMarkdown(item *ChangeItem) string {
+	var text strings.Builder
+
+	pos := 0
+	for _, link := range item.Links {
+		if link.Text == "" {
+			continue
+		}
+
+		// Specs written without offsets fall back to the next occurrence of the link text
+		i := link.Offset
+		if i < pos || !strings.HasPrefix(item.Text[min(i, len(item.Text)):], link.Text) {
+			if i = strings.Index(item.Text[pos:], link.Text); i < 0 {
+				continue
+			}
+			i += pos
+		}
+
+		text.WriteString(item.Text[pos:i])
+		text.WriteString("[" + link.Text + "](" + getDocumentationLink(link.Anchor) + ")")
+		pos = i + len(link.Text)
 	}
+	text.WriteString(item.Text[pos:])
 
-	return
+	return text.String()
 }
 
-func generateRequestTestFile(tmpl *template.Template, types Types, method *Method) (err error) {
+func generateTypes(types Types, methods Methods) (err error) {
 	var file *os.File
-	if file, err = os.Create(filepath.Join(ApiDir, RequestsDir, strcase.ToSnake(method.Key)+"_test.go")); err != nil {
+	if file, err = os.Create(filepath.Join(ApiDir, TypesFile)); err != nil {
 		return
 	}
 	//goland:noinspection GoUnhandledErrorResult
 	defer file.Close()
 
-	data := buildRequestTemplateData(types, method)
-	if err = tmpl.ExecuteTemplate(file, RequestTestTemplate, &data); err != nil {
+	var tmpl *template.Template
+	if tmpl, err = parseTemplates(TypesHeaderTemplate, TypesTemplate, InterfaceTemplate, ShapeTemplate, UnionTemplate, EnumTemplate); err != nil {
 		return
 	}
 
-	return
-}
-
-func buildRequestTemplateData(types Types, method *Method) RequestTemplateData {
-	imports := map[string]bool{
-		"io": true,
-	}
+	enumItems, enums := buildEnumTemplateData(types, methods)
 
-	data := RequestTemplateData{
-		Method:       method,
-		Name:         cases.Title(language.English, cases.NoLower).String(method.Key),
-		ResponseType: getGoType(types, method.ReturnType, true, "telegram"),
+	keys := types.GetFilteredKeys()
+	items := make([]TypeTemplateData, 0, len(keys))
+	hasConstFields := false
+	for _, key := range keys {
+		data := buildTypeTemplateData(types, enums, types[key])
+		if len(data.ConstFields) > 0 {
+			hasConstFields = true
+		}
 
-		Files: Files{
-			DirectFields: make([]FileField, 0),
-			Fields:       make(map[string][]FileField),
-			Arrays:       make(map[string][]FileField),
-			Subtypes:     make(map[string][]FileSubtype),
-			Variants:     make(map[string][]FileVariants),
-		},
+		items = append(items, data)
 	}
 
-	if t, ok := types[method.ReturnType]; ok && len(t.Subtypes) > 0 {
-		data.ResponseTypeVariants = make([]string, 0, len(t.Subtypes))
-		for _, subtype := range t.Subtypes {
-			data.ResponseTypeVariants = append(data.ResponseTypeVariants, getGoType(types, subtype, false, "telegram"))
-		}
+	unions := buildUnionTemplateData(types, methods)
 
-		imports["errors"] = true
+	header := TypesHeaderTemplateData{
+		Imports: make([]string, 0),
+	}
+	if len(unions) > 0 || len(types.GetPolymorphicKeys()) > 0 || hasConstFields {
+		header.Imports = append(header.Imports, "encoding/json")
+	}
+	if len(types.GetPolymorphicKeys()) > 0 {
+		header.Imports = append(header.Imports, "fmt")
 	}
 
-	fields := make([]RequestFieldTemplateData, 0, len(method.Fields))
-	for _, field := range method.Fields {
-		typeVariants := strings.Split(field.Type, " or ")
-		variantSimples := make([]RequestFieldTemplateData, 0, len(typeVariants))
-		variantObjects := make([]RequestFieldTemplateData, 0, len(typeVariants))
-		if len(typeVariants) > 1 {
-			for _, subtype := range typeVariants {
-				isArray := isArrayType(subtype)
-				isObject := isObjectType(subtype)
-				isInputFile := isInputFileType(subtype)
-				isChatId := isChatIdType(subtype)
+	if err = tmpl.ExecuteTemplate(file, TypesHeaderTemplate, header); err != nil {
+		return
+	}
 
-				if subtype == "int64" || subtype == "float64" {
-					imports["strconv"] = true
-				} else if isObject && !isInputFile && !isChatId || isArray {
-					imports["encoding/json"] = true
-				}
+	for _, data := range items {
+		if err = tmpl.ExecuteTemplate(file, TypesTemplate, data); err != nil {
+			return
+		}
+	}
 
-				requestFieldVariant := RequestFieldTemplateData{
-					Type:        getGoType(types, subtype, true, "telegram"),
-					IsArray:     isArray,
-					IsObject:    isObject,
-					IsInputFile: isInputFile,
-					IsChatId:    isChatId,
-				}
+	interfaces := make([]InterfaceTemplateData, 0)
+	hasShapes := false
+	for _, key := range types.GetPolymorphicKeys() {
+		data := buildInterfaceTemplateData(types, types[key])
 
-				if isObject || isArray {
-					variantObjects = append(variantObjects, requestFieldVariant)
-				} else {
-					variantSimples = append(variantSimples, requestFieldVariant)
-				}
+		var missing []string
+		if data.Samples, missing = getInterfaceSamples(types, data); len(missing) > 0 {
+			for _, subtype := range missing {
+				log.Printf("warning: %s subtype %s cannot be told apart from other subtypes\n", key, subtype)
 			}
-
-			imports["errors"] = true
 		}
 
-		variants := make([][]RequestFieldTemplateData, 0, len(variantSimples)+1)
-		for _, variant := range variantSimples {
-			variants = append(variants, []RequestFieldTemplateData{variant})
+		if err = tmpl.ExecuteTemplate(file, InterfaceTemplate, data); err != nil {
+			return
 		}
-		if len(variantObjects) > 0 {
-			variants = append(variants, variantObjects)
+
+		if data.Discriminator == "" {
+			hasShapes = true
 		}
 
-		isArray := isArrayType(field.Type)
-		isObject := isObjectType(field.Type)
-		isInputFile := isInputFileType(field.Type)
-		isChatId := isChatIdType(field.Type)
+		if len(data.Samples) > 0 {
+			interfaces = append(interfaces, data)
+		}
+	}
 
-		if field.Type == "int64" || field.Type == "float64" {
-			imports["strconv"] = true
-		} else if isObject && !isInputFile && !isChatId || isArray {
-			imports["encoding/json"] = true
+	if hasShapes {
+		if err = tmpl.ExecuteTemplate(file, ShapeTemplate, nil); err != nil {
+			return
 		}
+	}
 
-		requestField := RequestFieldTemplateData{
+	for _, union := range unions {
+		if err = tmpl.ExecuteTemplate(file, UnionTemplate, union); err != nil {
+			return
+		}
+	}
+
+	for _, enum := range enumItems {
+		if err = tmpl.ExecuteTemplate(file, EnumTemplate, enum); err != nil {
+			return
+		}
+	}
+
+	if len(interfaces) > 0 || len(unions) > 0 {
+		tests := TypesTestTemplateData{
+			Interfaces: interfaces,
+			Unions:     unions,
+		}
+
+		if err = generateTemplateFile(TypesTestTemplate, filepath.Join(ApiDir, TypesTestFile), &tests); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func buildTypeTemplateData(types Types, enums Enums, item *Type) TypeTemplateData {
+	data := TypeTemplateData{
+		Type:              item,
+		Fields:            make([]*TypeFieldTemplateData, 0, len(item.Fields)),
+		ConstFields:       make([]*TypeFieldTemplateData, 0),
+		PolymorphicFields: make([]*TypeFieldTemplateData, 0),
+	}
+
+	for _, field := range item.Fields {
+		fieldData := &TypeFieldTemplateData{
+			Field:   field,
+			Name:    strcase.ToCamel(field.Key),
+			Type:    getGoType(types, field.Type, field.IsRequired, ""),
+			IsArray: isArrayType(field.Type),
+		}
+
+		if enum, ok := enums[field]; ok {
+			fieldData.Type = getEnumGoType(enum, field.IsRequired, "")
+		}
+
+		if field.Const != "" {
+			data.ConstFields = append(data.ConstFields, fieldData)
+			continue
+		}
+
+		if elementType := strings.TrimPrefix(field.Type, "[]"); types.IsPolymorphic(elementType) {
+			fieldData.Interface = elementType
+			data.PolymorphicFields = append(data.PolymorphicFields, fieldData)
+		}
+
+		data.Fields = append(data.Fields, fieldData)
+	}
+
+	data.SortFields()
+	sort.Slice(data.ConstFields, func(i, j int) bool {
+		return data.ConstFields[i].Field.Key < data.ConstFields[j].Field.Key
+	})
+	sort.Slice(data.PolymorphicFields, func(i, j int) bool {
+		return data.PolymorphicFields[i].Field.Key < data.PolymorphicFields[j].Field.Key
+	})
+
+	return data
+}
+
+func buildEnumTemplateData(types Types, methods Methods) (items []EnumTemplateData, enums Enums) {
+	items = make([]EnumTemplateData, 0)
+	enums = make(Enums)
+
+	type enumOwner struct {
+		name  string
+		field *Field
+	}
+
+	keys := make([]string, 0)
+	owners := make(map[string][]enumOwner)
+	add := func(owner string, field *Field) {
+		values := slices.Clone(field.Enum)
+		sort.Strings(values)
+
+		key := strings.Join(values, "|")
+		if _, ok := owners[key]; !ok {
+			keys = append(keys, key)
+		}
+		owners[key] = append(owners[key], enumOwner{name: owner, field: field})
+	}
+
+	for _, key := range types.GetFilteredKeys() {
+		for _, fieldKey := range types[key].Fields.GetSortedKeys() {
+			if field := types[key].Fields[fieldKey]; len(field.Enum) > 0 {
+				add(key, field)
+			}
+		}
+	}
+
+	for _, key := range methods.GetSortedKeys() {
+		for _, fieldKey := range methods[key].Fields.GetSortedKeys() {
+			if field := methods[key].Fields[fieldKey]; len(field.Enum) > 0 {
+				add(strcase.ToCamel(key), field)
+			}
+		}
+	}
+
+	names := getGeneratedNames(types, methods)
+	for _, key := range keys {
+		name := getEnumName(owners[key][0].name, owners[key][0].field.Key)
+		if len(owners[key]) > 1 {
+			// A shared enum is named after the most specific field key, not after whichever owner comes first
+			fieldKey, owner := "", ""
+			for _, item := range owners[key] {
+				if len(item.field.Key) > len(fieldKey) || len(item.field.Key) == len(fieldKey) && item.field.Key < fieldKey {
+					fieldKey, owner = item.field.Key, item.name
+				} else if item.field.Key == fieldKey && item.name < owner {
+					owner = item.name
+				}
+			}
+
+			name = strcase.ToCamel(fieldKey)
+			if !strings.Contains(fieldKey, "_") {
+				name = getEnumName(owner, fieldKey)
+			}
+		}
+
+		// The name and every value constant must be free among the generated identifiers
+		base := name
+		for i := 1; isNameTaken(names, name, owners[key][0].field.Enum); i++ {
+			name = base + "Value"
+			if i > 1 {
+				name += strconv.Itoa(i)
+			}
+		}
+
+		item := EnumTemplateData{
+			Name:        name,
+			Description: owners[key][0].field.Description,
+			Values:      make([]EnumValueTemplateData, 0, len(owners[key][0].field.Enum)),
+		}
+		for _, value := range owners[key][0].field.Enum {
+			item.Values = append(item.Values, EnumValueTemplateData{
+				Name:  name + strcase.ToCamel(value),
+				Value: value,
+			})
+		}
+
+		items = append(items, item)
+		names[name] = true
+		for _, value := range item.Values {
+			names[value.Name] = true
+		}
+		for _, owner := range owners[key] {
+			enums[owner.field] = name
+		}
+	}
+
+	return
+}
+
+// getGeneratedNames returns the identifiers of the telegram package that are generated besides enums.
+func getGeneratedNames(types Types, methods Methods) (names map[string]bool) {
+	names = map[string]bool{
+		"ApiVersion":     true,
+		"ApiReleaseDate": true,
+	}
+
+	for key := range types {
+		names[key] = true
+		if types.IsPolymorphic(key) {
+			names[getInterfaceDecoderName(key)] = true
+		}
+	}
+
+	for _, union := range buildUnionTemplateData(types, methods) {
+		names[union.Name] = true
+	}
+
+	if update, ok := types[UpdateType]; ok {
+		for _, name := range []string{"UpdateType", "AllowedUpdate", "AllUpdates", "DefaultUpdates", "Router", "NewRouter"} {
+			names[name] = true
+		}
+
+		for key := range update.Fields {
+			names["UpdateType"+strcase.ToCamel(key)] = true
+			names["AllowedUpdate"+strcase.ToCamel(key)] = true
+		}
+	}
+
+	return
+}
+
+func isNameTaken(names map[string]bool, name string, values []string) bool {
+	if names[name] {
+		return true
+	}
+
+	for _, value := range values {
+		if names[name+strcase.ToCamel(value)] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getEnumName(owner string, key string) string {
+	return owner + strcase.ToCamel(key)
+}
+
+func getEnumGoType(name string, isRequired bool, pkg string) (t string) {
+	t = name
+	if pkg != "" {
+		t = pkg + "." + t
+	}
+
+	if !isRequired {
+		t = "*" + t
+	}
+
+	return
+}
+
+func buildInterfaceTemplateData(types Types, item *Type) InterfaceTemplateData {
+	data := InterfaceTemplateData{
+		Type:     item,
+		Name:     item.Name,
+		Method:   getInterfaceMethodName(item.Name),
+		Subtypes: make([]string, 0, len(item.Subtypes)),
+	}
+
+	for _, subtype := range item.Subtypes {
+		if _, ok := types[subtype]; ok {
+			data.Subtypes = append(data.Subtypes, subtype)
+		}
+	}
+
+	var values map[string]string
+	if data.Discriminator, values = types.GetDiscriminator(item.Name); data.Discriminator != "" {
+		data.Variants = make([]InterfaceVariantTemplateData, 0, len(values))
+		for _, subtype := range data.Subtypes {
+			data.Variants = append(data.Variants, InterfaceVariantTemplateData{
+				Value: types[subtype].Fields[data.Discriminator].Const,
+				Type:  subtype,
+			})
+		}
+	} else {
+		data.Shapes = make([]InterfaceShapeTemplateData, 0, len(data.Subtypes))
+		for _, subtype := range data.Subtypes {
+			data.Shapes = append(data.Shapes, getObjectShape(types[subtype]))
+		}
+
+		sort.SliceStable(data.Shapes, func(i, j int) bool {
+			return len(data.Shapes[i].Known) < len(data.Shapes[j].Known)
+		})
+	}
+
+	return data
+}
+
+// getInterfaceSamples returns a JSON sample of every subtype and the subtypes that cannot be told apart
+// from the others.
+func getInterfaceSamples(types Types, data InterfaceTemplateData) (samples []InterfaceSampleTemplateData, missing []string) {
+	samples = make([]InterfaceSampleTemplateData, 0, len(data.Subtypes))
+	for _, subtype := range data.Subtypes {
+		// Input types are only sent to the API and are never decoded
+		if hasInputFiles(types, subtype) {
+			continue
+		}
+
+		if sample, ok := getInterfaceSample(types, data, subtype); ok {
+			samples = append(samples, InterfaceSampleTemplateData{
+				Type: subtype,
+				Json: sample,
+			})
+		} else {
+			missing = append(missing, subtype)
+		}
+	}
+
+	return
+}
+
+func getObjectShape(t *Type) (shape InterfaceShapeTemplateData) {
+	shape = InterfaceShapeTemplateData{
+		Type:     t.Name,
+		Required: make([]string, 0),
+		Known:    t.Fields.GetSortedKeys(),
+	}
+
+	for _, key := range shape.Known {
+		field := t.Fields[key]
+		if field.IsRequired {
+			shape.Required = append(shape.Required, key)
+		}
+
+		if field.Const != "" {
+			if shape.Consts == nil {
+				shape.Consts = make(map[string]string)
+			}
+
+			shape.Consts[key] = field.Const
+		}
+	}
+
+	return
+}
+
+// getInterfaceSample returns a JSON object that decodes to the subtype. Fields unique to the subtype
+// are added one by one until the object is not mistaken for another subtype.
+func getInterfaceSample(types Types, data InterfaceTemplateData, subtype string) (sample string, ok bool) {
+	fields := getSampleFields(types, types[subtype], 0)
+	if data.Discriminator != "" {
+		return encodeSampleFields(fields), true
+	}
+
+	extra := make([]string, 0)
+	for _, key := range types[subtype].Fields.GetSortedKeys() {
+		if _, ok = fields[key]; !ok {
+			extra = append(extra, key)
+		}
+	}
+
+	for i := 0; ; i++ {
+		if matchSampleShape(data.Shapes, fields) == subtype {
+			return encodeSampleFields(fields), true
+		}
+
+		if i == len(extra) {
+			return "", false
+		}
+
+		fields[extra[i]] = getSampleJson(types, types[subtype].Fields[extra[i]].Type, 1)
+	}
+}
+
+// matchSampleShape mirrors matchObjectShape of the generated code.
+func matchSampleShape(shapes []InterfaceShapeTemplateData, fields map[string]string) (match string) {
+	best := -1
+	for _, shape := range shapes {
+		score := 0
+		for _, key := range shape.Required {
+			if _, ok := fields[key]; !ok {
+				score = -1
+				break
+			}
+		}
+
+		for key, value := range shape.Consts {
+			if fields[key] != strconv.Quote(value) {
+				score = -1
+			}
+		}
+
+		if score < 0 {
+			continue
+		}
+
+		for _, key := range shape.Known {
+			if _, ok := fields[key]; ok {
+				score++
+			}
+		}
+
+		if score > best {
+			match, best = shape.Type, score
+		}
+	}
+
+	return
+}
+
+func getSampleFields(types Types, t *Type, depth int) (fields map[string]string) {
+	fields = make(map[string]string)
+	for key, field := range t.Fields {
+		if field.Const != "" {
+			fields[key] = strconv.Quote(field.Const)
+		} else if field.IsRequired {
+			fields[key] = getSampleJson(types, field.Type, depth+1)
+		}
+	}
+
+	return
+}
+
+func getSampleJson(types Types, value string, depth int) string {
+	value = strings.Split(value, " or ")[0]
+	if isArrayType(value) {
+		return "[" + getSampleJson(types, value[2:], depth) + "]" // len("[]") == 2
+	}
+
+	switch value {
+	case "string", InputFileType:
+		return `"x"`
+	case "int64", ChatIdType:
+		return "1"
+	case "float64":
+		return "1.5"
+	case "bool":
+		return "true"
+	}
+
+	t, ok := types[value]
+	if !ok || depth > 3 {
+		return "{}"
+	}
+
+	if len(t.Subtypes) > 0 {
+		if t, ok = types[t.Subtypes[0]]; !ok {
+			return "{}"
+		}
+	}
+
+	return encodeSampleFields(getSampleFields(types, t, depth))
+}
+
+func encodeSampleFields(fields map[string]string) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	items := make([]string, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, strconv.Quote(key)+":"+fields[key])
+	}
+
+	return "{" + strings.Join(items, ",") + "}"
+}
+
+func getInterfaceDecoderName(name string) string {
+	return "Unmarshal" + name
+}
+
+func getInterfaceMethodName(name string) string {
+	return "is" + name
+}
+
+func buildUnionTemplateData(types Types, methods Methods) (unions []UnionTemplateData) {
+	returnTypes := make(map[string]bool)
+	for _, method := range methods {
+		if isUnionType(method.ReturnType) {
+			returnTypes[method.ReturnType] = true
+		}
+	}
+
+	unions = make([]UnionTemplateData, 0, len(returnTypes))
+	for returnType := range returnTypes {
+		variants := strings.Split(returnType, " or ")
+
+		union := UnionTemplateData{
+			Name:     getUnionTypeName(returnType),
+			Variants: make([]UnionVariantTemplateData, 0, len(variants)),
+		}
+
+		for _, variant := range variants {
+			union.Variants = append(union.Variants, UnionVariantTemplateData{
+				Name:    getUnionVariantName(variant),
+				Type:    getGoType(types, variant, true, ""),
+				IsArray: isArrayType(variant),
+				Json:    getSampleJson(types, variant, 0),
+			})
+		}
+
+		unions = append(unions, union)
+	}
+
+	sort.Slice(unions, func(i, j int) bool {
+		return unions[i].Name < unions[j].Name
+	})
+
+	return
+}
+
+func generateUpdates(types Types, methods Methods) (err error) {
+	update, ok := types[UpdateType]
+	if !ok {
+		return
+	}
+
+	var file *os.File
+	if file, err = os.Create(filepath.Join(ApiDir, UpdatesFile)); err != nil {
+		return
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer file.Close()
+
+	var tmpl *template.Template
+	if tmpl, err = parseTemplates(UpdatesTemplate); err != nil {
+		return
+	}
+
+	data := UpdatesTemplateData{
+		Fields: make([]UpdateFieldTemplateData, 0, len(update.Fields)),
+	}
+
+	explicit := getExplicitUpdates(update, methods[GetUpdatesMethod])
+	for _, key := range update.Fields.GetSortedKeys() {
+		field := update.Fields[key]
+		if field.IsRequired {
+			continue
+		}
+
+		data.Fields = append(data.Fields, UpdateFieldTemplateData{
+			Field: field,
+			Name:  strcase.ToCamel(field.Key),
+			Const: "UpdateType" + strcase.ToCamel(field.Key),
+			Param: getParamName(field.Key),
+			Type:  getGoType(types, field.Type, false, ""),
+
+			IsExplicit: explicit[field.Key],
+		})
+
+		if item := &data.Fields[len(data.Fields)-1]; strings.HasPrefix(item.Type, "*") {
+			item.Value = "&" + item.Type[1:] + "{}"
+		}
+	}
+
+	if err = tmpl.ExecuteTemplate(file, UpdatesTemplate, &data); err != nil {
+		return
+	}
+
+	// The router test registers a handler for one update type and leaves another one to the fallback
+	for i := range data.Fields {
+		if data.Fields[i].Value == "" {
+			continue
+		}
+
+		if data.Handled == nil {
+			data.Handled = &data.Fields[i]
+		} else if data.Unhandled == nil {
+			data.Unhandled = &data.Fields[i]
+		}
+	}
+
+	if err = generateTemplateFile(UpdatesTestTemplate, filepath.Join(ApiDir, UpdatesTestFile), &data); err != nil {
+		return
+	}
+
+	return
+}
+
+// getExplicitUpdates returns the update types that are not received unless listed in allowed_updates.
+// They are taken from the "except ..." list in the allowed_updates description of getUpdates and from
+// the update fields that ask to specify their own key, e.g. "must explicitly specify “chat_member”".
+func getExplicitUpdates(update *Type, method *Method) (explicit map[string]bool) {
+	explicit = make(map[string]bool)
+
+	listed := make(map[string]bool)
+	if method != nil && method.Fields[AllowedUpdatesField] != nil {
+		re := regexp.MustCompile(`\bexcept ([a-z_]+(?:,? (?:and )?[a-z_]+)*)`)
+		if match := re.FindStringSubmatch(method.Fields[AllowedUpdatesField].Description); match != nil {
+			for _, key := range regexp.MustCompile(`[a-z_]+`).FindAllString(match[1], -1) {
+				if key == "and" {
+					continue
+				}
+
+				if field, ok := update.Fields[key]; !ok || field.IsRequired {
+					log.Printf("warning: %s lists unknown update type %q\n", AllowedUpdatesField, key)
+					continue
+				}
+
+				listed[key] = true
+			}
+		}
+	}
+
+	marked := make(map[string]bool)
+	for key, field := range update.Fields {
+		if !field.IsRequired && strings.Contains(field.Description, "specify “"+key+"”") {
+			marked[key] = true
+		}
+	}
+
+	if len(listed) == 0 && len(marked) == 0 {
+		log.Println("warning: no update types that must be explicitly allowed found, DefaultUpdates returns every type")
+	}
+
+	for _, key := range update.Fields.GetSortedKeys() {
+		if len(listed) > 0 && len(marked) > 0 && listed[key] != marked[key] {
+			log.Printf("warning: update type %q is not marked as explicit in both %s and its description\n", key, AllowedUpdatesField)
+		}
+
+		explicit[key] = listed[key] || marked[key]
+	}
+
+	return
+}
+
+func generateWebhook(types Types) (err error) {
+	if _, ok := types[UpdateType]; !ok {
+		return
+	}
+
+	webhookDirPath := filepath.Join(ApiDir, WebhookDir)
+	if err = os.RemoveAll(webhookDirPath); err != nil {
+		return
+	}
+
+	if err = os.Mkdir(webhookDirPath, 0o755); err != nil {
+		return
+	}
+
+	files := map[string]string{
+		WebhookTemplate:     "handler.go",
+		WebhookTestTemplate: "handler_test.go",
+	}
+
+	for name, fileName := range files {
+		if err = generateTemplateFile(name, filepath.Join(webhookDirPath, fileName), nil); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func generateTemplateFile(name string, path string, data interface{}, includes ...string) (err error) {
+	var tmpl *template.Template
+	if tmpl, err = parseTemplates(append([]string{name}, includes...)...); err != nil {
+		return
+	}
+
+	var file *os.File
+	if file, err = os.Create(path); err != nil {
+		return
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer file.Close()
+
+	if err = tmpl.ExecuteTemplate(file, name, data); err != nil {
+		return
+	}
+
+	return
+}
+
+func generateRequests(types Types, methods Methods) (err error) {
+	_, enums := buildEnumTemplateData(types, methods)
+
+	requestsDirPath := filepath.Join(ApiDir, RequestsDir)
+	if err = os.RemoveAll(requestsDirPath); err != nil {
+		return
+	}
+
+	if err = os.Mkdir(requestsDirPath, 0o755); err != nil {
+		return
+	}
+
+	var helpersTmpl *template.Template
+	if helpersTmpl, err = parseTemplates(HelpersTestTemplate); err != nil {
+		return
+	}
+
+	var helpersFile *os.File
+	if helpersFile, err = os.Create(filepath.Join(requestsDirPath, "helpers_test.go")); err != nil {
+		return
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer helpersFile.Close()
+	if err = helpersTmpl.ExecuteTemplate(helpersFile, HelpersTestTemplate, nil); err != nil {
+		return
+	}
+
+	var reqTmpl, testTmpl *template.Template
+	if reqTmpl, err = parseTemplates(RequestFileTemplate, ShapeTemplate); err != nil {
+		return
+	}
+	if testTmpl, err = parseTemplates(RequestTestTemplate); err != nil {
+		return
+	}
+
+	for _, item := range methods {
+		if err = generateRequestFile(reqTmpl, types, enums, item); err != nil {
+			return
+		}
+
+		if err = generateRequestTestFile(testTmpl, types, enums, item); err != nil {
+			return
+		}
+	}
+
+	data := RequestsTemplateData{
+		Requests: make([]RequestTemplateData, 0, len(methods)),
+	}
+
+	for _, key := range methods.GetSortedKeys() {
+		data.Requests = append(data.Requests, buildRequestTemplateData(types, enums, methods[key]))
+	}
+
+	if err = generateTemplateFile(ClientTemplate, filepath.Join(ApiDir, RequestsDir, ClientFile), &data); err != nil {
+		return
+	}
+
+	if err = generateTemplateFile(ClientTestTemplate, filepath.Join(ApiDir, RequestsDir, ClientTestFile), &data); err != nil {
+		return
+	}
+
+	if err = generateTemplateFile(RegistryTemplate, filepath.Join(ApiDir, RequestsDir, RegistryFile), &data, ShapeTemplate); err != nil {
+		return
+	}
+
+	for _, request := range data.Requests {
+		for _, field := range request.DecodedFields {
+			if field.Decoder == "" && len(field.Variants) < len(strings.Split(request.Method.Fields[field.Key].Type, " or ")) {
+				log.Printf("warning: %s field %s has variants that cannot be decoded from JSON\n", request.Method.Key, field.Key)
+			}
+		}
+	}
+
+	data.DecodeTests = buildRegistryDecodeTests(types, data.Requests)
+	if err = generateTemplateFile(RegistryTestTemplate, filepath.Join(ApiDir, RequestsDir, RegistryTestFile), &data); err != nil {
+		return
+	}
+
+	if err = generatePoller(types, methods); err != nil {
+		return
+	}
+
+	return
+}
+
+func generatePoller(types Types, methods Methods) (err error) {
+	update, ok := types[UpdateType]
+	if !ok {
+		return
+	}
+
+	method, ok := methods[GetUpdatesMethod]
+	if !ok {
+		return
+	}
+
+	offset, timeout, updateId := method.Fields["offset"], method.Fields["timeout"], update.Fields["update_id"]
+	if offset == nil || timeout == nil || updateId == nil || offset.Type != "int64" || updateId.Type != "int64" {
+		return
+	}
+
+	data := PollerTemplateData{
+		Name:           cases.Title(language.English, cases.NoLower).String(method.Key),
+		Offset:         strcase.ToCamel(offset.Key),
+		Timeout:        strcase.ToCamel(timeout.Key),
+		UpdateId:       strcase.ToCamel(updateId.Key),
+		DefaultTimeout: DefaultPollTimeout,
+	}
+
+	files := map[string]string{
+		PollerTemplate:     "poller.go",
+		PollerTestTemplate: "poller_test.go",
+	}
+
+	for name, fileName := range files {
+		if err = generateTemplateFile(name, filepath.Join(ApiDir, RequestsDir, fileName), &data); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func generateRequestFile(tmpl *template.Template, types Types, enums Enums, method *Method) (err error) {
+	var file *os.File
+	if file, err = os.Create(filepath.Join(ApiDir, RequestsDir, strcase.ToSnake(method.Key)+".go")); err != nil {
+		return
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer file.Close()
+
+	data := buildRequestTemplateData(types, enums, method)
+	if err = tmpl.ExecuteTemplate(file, RequestFileTemplate, &data); err != nil {
+		return
+	}
+
+	return
+}
+
+func generateRequestTestFile(tmpl *template.Template, types Types, enums Enums, method *Method) (err error) {
+	var file *os.File
+	if file, err = os.Create(filepath.Join(ApiDir, RequestsDir, strcase.ToSnake(method.Key)+"_test.go")); err != nil {
+		return
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer file.Close()
+
+	data := buildRequestTemplateData(types, enums, method)
+	if err = tmpl.ExecuteTemplate(file, RequestTestTemplate, &data); err != nil {
+		return
+	}
+
+	return
+}
+
+func buildRequestTemplateData(types Types, enums Enums, method *Method) RequestTemplateData {
+	imports := map[string]bool{
+		"encoding/json": true,
+		"io":            true,
+	}
+
+	data := RequestTemplateData{
+		Method:       method,
+		Name:         cases.Title(language.English, cases.NoLower).String(method.Key),
+		ResponseType: getResponseType(types, method.ReturnType),
+		IsEmptyValid: true,
+
+		Files: Files{
+			DirectFields: make([]FileField, 0),
+			Fields:       make(map[string][]FileField),
+			Arrays:       make(map[string][]FileField),
+			Subtypes:     make(map[string][]FileSubtype),
+			Variants:     make(map[string][]FileVariants),
+		},
+	}
+
+	data.ResultType = data.ResponseType
+	if isObjectType(method.ReturnType) && !isUnionType(method.ReturnType) && !types.IsPolymorphic(method.ReturnType) {
+		data.ResultType = "*" + data.ResponseType
+	}
+
+	if elementType := strings.TrimPrefix(method.ReturnType, "[]"); types.IsPolymorphic(elementType) {
+		data.ResponseDecoder = "telegram." + getInterfaceDecoderName(elementType)
+		data.ResponseItemType = getGoType(types, elementType, true, "telegram")
+		data.IsResponseArray = isArrayType(method.ReturnType)
+
+		imports["encoding/json"] = true
+	}
+
+	if data.Pagination = buildPaginationTemplateData(types, method); data.Pagination != nil {
+		imports["iter"] = true
+	}
+
+	if t, ok := types[method.ReturnType]; ok && len(t.Subtypes) > 0 {
+		data.ResponseTypeVariants = make([]string, 0, len(t.Subtypes))
+		for _, subtype := range t.Subtypes {
+			data.ResponseTypeVariants = append(data.ResponseTypeVariants, getGoType(types, subtype, false, "telegram"))
+		}
+
+		imports["errors"] = true
+	}
+
+	fields := make([]RequestFieldTemplateData, 0, len(method.Fields))
+	for _, field := range method.Fields {
+		typeVariants := strings.Split(field.Type, " or ")
+		variantSimples := make([]RequestFieldTemplateData, 0, len(typeVariants))
+		variantObjects := make([]RequestFieldTemplateData, 0, len(typeVariants))
+		if len(typeVariants) > 1 {
+			for _, subtype := range typeVariants {
+				isArray := isArrayType(subtype)
+				isObject := isObjectType(subtype)
+				isInputFile := isInputFileType(subtype)
+				isChatId := isChatIdType(subtype)
+
+				if subtype == "int64" || subtype == "float64" {
+					imports["strconv"] = true
+				} else if isObject && !isInputFile && !isChatId || isArray {
+					imports["encoding/json"] = true
+				}
+
+				requestFieldVariant := RequestFieldTemplateData{
+					Type:        getGoType(types, subtype, true, "telegram"),
+					IsArray:     isArray,
+					IsObject:    isObject,
+					IsInputFile: isInputFile,
+					IsChatId:    isChatId,
+				}
+
+				if isObject || isArray {
+					variantObjects = append(variantObjects, requestFieldVariant)
+				} else {
+					variantSimples = append(variantSimples, requestFieldVariant)
+				}
+			}
+
+			imports["errors"] = true
+		}
+
+		variants := make([][]RequestFieldTemplateData, 0, len(variantSimples)+1)
+		for _, variant := range variantSimples {
+			variants = append(variants, []RequestFieldTemplateData{variant})
+		}
+		if len(variantObjects) > 0 {
+			variants = append(variants, variantObjects)
+		}
+
+		isArray := isArrayType(field.Type)
+		isObject := isObjectType(field.Type)
+		isInputFile := isInputFileType(field.Type)
+		isChatId := isChatIdType(field.Type)
+
+		if field.Type == "int64" || field.Type == "float64" {
+			imports["strconv"] = true
+		} else if isObject && !isInputFile && !isChatId || isArray {
+			imports["encoding/json"] = true
+		}
+
+		requestField := RequestFieldTemplateData{
 			Field:       field,
 			Name:        strcase.ToCamel(field.Key),
 			Type:        getGoType(types, field.Type, field.IsRequired, "telegram"),
@@ -354,6 +1584,43 @@ func buildRequestTemplateData(types Types, method *Method) RequestTemplateData {
 			Variants:    variants,
 		}
 
+		requestField.IsInterface = len(variants) > 0 || requestField.Type == "interface{}" || types.IsPolymorphic(field.Type)
+
+		if enum, ok := enums[field]; ok {
+			requestField.Type = getEnumGoType(enum, field.IsRequired, "telegram")
+			requestField.Enum = "telegram." + enum
+
+			imports["errors"] = true
+		}
+
+		if _, ok := types[UpdateType]; ok && field.Key == AllowedUpdatesField && field.Type == "[]string" {
+			requestField.Type = "[]telegram.AllowedUpdate"
+		}
+
+		requestField.Param = getParamName(field.Key)
+		if len(variants) > 0 {
+			requestField.ParamType = "interface{}"
+		} else {
+			requestField.ParamType = strings.TrimPrefix(requestField.Type, "*")
+		}
+
+		if field.IsRequired && (field.Type == "string" || isChatId || requestField.IsInterface) {
+			data.IsEmptyValid = false
+			imports["errors"] = true
+		}
+
+		if c := field.Constraint; c != nil {
+			if c.AfterEntitiesParsing {
+				requestField.ParseMode, requestField.Entities = getEntitiesFields(method.Fields, field.Key)
+			}
+
+			imports["errors"] = true
+
+			if field.IsRequired && (c.Min > 0 || c.Max < 0) {
+				data.IsEmptyValid = false
+			}
+		}
+
 		fields = append(fields, requestField)
 
 		// Check if this is a direct InputFile field
@@ -369,7 +1636,7 @@ func buildRequestTemplateData(types Types, method *Method) RequestTemplateData {
 			for _, subtype := range t.Subtypes {
 				if inputFileFields := getInputFileFields(types[subtype].Fields); len(inputFileFields) > 0 {
 					subtypes = append(subtypes, FileSubtype{
-						Type:   getGoType(types, subtype, true, "telegram"),
+						Type:   getGoType(types, subtype, false, "telegram"),
 						Fields: inputFileFields,
 					})
 				}
@@ -416,6 +1683,12 @@ func buildRequestTemplateData(types Types, method *Method) RequestTemplateData {
 	data.Fields = fields
 	data.SortFields()
 
+	data.ValidateTests = buildValidateTestsTemplateData(types, data)
+	data.DecodedFields = buildRequestDecodedFields(types, data)
+	data.JsonTests = buildJsonTestsTemplateData(data)
+	data.DoTest = buildDoTestTemplateData(types, data)
+	data.ConstructorTests = buildConstructorTestsTemplateData(types, data)
+
 	data.Imports = make([]string, 0)
 	for module := range imports {
 		data.Imports = append(data.Imports, module)
@@ -425,6 +1698,109 @@ func buildRequestTemplateData(types Types, method *Method) RequestTemplateData {
 	return data
 }
 
+func buildPaginationTemplateData(types Types, method *Method) *PaginationTemplateData {
+	offset, limit := method.Fields["offset"], method.Fields["limit"]
+	if method.Key == GetUpdatesMethod || offset == nil || limit == nil || offset.IsRequired || limit.Type != "int64" {
+		return nil
+	}
+
+	data := &PaginationTemplateData{
+		Offset:          strcase.ToCamel(offset.Key),
+		Limit:           strcase.ToCamel(limit.Key),
+		DefaultLimit:    getDefaultLimit(limit.Description),
+		IsLimitRequired: limit.IsRequired,
+	}
+
+	var result *Type
+	if isArrayType(method.ReturnType) && !isUnionType(method.ReturnType) {
+		data.ItemType = getGoType(types, method.ReturnType[2:], true, "telegram") // len("[]") == 2
+	} else if result = types[method.ReturnType]; result != nil && len(result.Subtypes) == 0 {
+		for _, key := range result.Fields.GetSortedKeys() {
+			field := result.Fields[key]
+			if !isArrayType(field.Type) || isUnionType(field.Type) {
+				continue
+			}
+
+			if data.Items != "" {
+				return nil
+			}
+
+			data.Items = strcase.ToCamel(field.Key)
+			data.ItemType = getGoType(types, field.Type[2:], true, "telegram") // len("[]") == 2
+			data.Result = "telegram." + result.Name
+		}
+	}
+
+	if data.ItemType == "" {
+		return nil
+	}
+
+	switch offset.Type {
+	case "int64":
+	case "string":
+		if result == nil {
+			return nil
+		}
+
+		nextOffset := result.Fields["next_offset"]
+		if nextOffset == nil || nextOffset.Type != "string" {
+			return nil
+		}
+
+		data.NextOffset = strcase.ToCamel(nextOffset.Key)
+		data.IsNextOffsetRequired = nextOffset.IsRequired
+	default:
+		return nil
+	}
+
+	data.IsTelegramUsed = data.Result != "" || strings.Contains(data.ItemType, "telegram.")
+
+	return data
+}
+
+// getDefaultLimit returns the page size used by the API when the limit is not set, e.g. "Defaults to 100".
+func getDefaultLimit(desc string) (limit int64) {
+	if match := regexp.MustCompile(`\bDefaults to (\d+)\b`).FindStringSubmatch(desc); match != nil {
+		limit, _ = strconv.ParseInt(match[1], 10, 64)
+	}
+
+	return
+}
+
+func getParamName(key string) (name string) {
+	name = strcase.ToLowerCamel(key)
+	if token.IsKeyword(name) {
+		name += "_"
+	}
+
+	return
+}
+
+func getResponseType(types Types, value string) string {
+	if isUnionType(value) {
+		return "telegram." + getUnionTypeName(value)
+	}
+
+	return getGoType(types, value, true, "telegram")
+}
+
+func getUnionTypeName(value string) string {
+	variants := strings.Split(value, " or ")
+	for i, variant := range variants {
+		variants[i] = getUnionVariantName(variant)
+	}
+
+	return strings.Join(variants, "Or")
+}
+
+func getUnionVariantName(value string) string {
+	if isArrayType(value) {
+		return "ArrayOf" + getUnionVariantName(value[2:]) // len("[]") == 2
+	}
+
+	return strcase.ToCamel(value)
+}
+
 func getInputFileFields(fields Fields) (output []FileField) {
 	output = make([]FileField, 0)
 	for _, field := range fields {
@@ -441,18 +1817,514 @@ func getInputFileFields(fields Fields) (output []FileField) {
 	return
 }
 
-func getGoType(types Types, value string, isRequired bool, pkg string) (t string) {
-	hasSubtypes := false
-	if t, ok := types[value]; ok && len(t.Subtypes) > 0 {
-		hasSubtypes = true
+// buildRequestDecodedFields returns the fields that encoding/json cannot decode on its own: interfaces
+// of polymorphic types and fields accepting values of several types.
+func buildRequestDecodedFields(types Types, data RequestTemplateData) (fields []RequestDecodedFieldTemplateData) {
+	fields = make([]RequestDecodedFieldTemplateData, 0)
+	for _, field := range data.Fields {
+		decoded := RequestDecodedFieldTemplateData{
+			Name: field.Name,
+			Key:  field.Field.Key,
+		}
+
+		switch value := field.Field.Type; {
+		case len(field.Variants) > 0:
+			decoded.Variants = getRequestDecodedVariants(types, field.Field)
+		case types.IsPolymorphic(value):
+			decoded.Decoder = "telegram." + getInterfaceDecoderName(value)
+		case isArrayType(value) && types.IsPolymorphic(value[2:]): // len("[]") == 2
+			decoded.Decoder = "telegram." + getInterfaceDecoderName(value[2:])
+			decoded.IsArray = true
+		default:
+			continue
+		}
+
+		fields = append(fields, decoded)
+	}
+
+	return
+}
+
+func getRequestDecodedVariants(types Types, field *Field) (variants []RequestDecodedVariantTemplateData) {
+	variants = make([]RequestDecodedVariantTemplateData, 0)
+	for _, value := range strings.Split(field.Type, " or ") {
+		variant := RequestDecodedVariantTemplateData{
+			Type:    getGoType(types, value, true, "telegram"),
+			ApiType: value,
+		}
+
+		element := strings.TrimPrefix(value, "[]")
+		switch {
+		case value == "string":
+			variant.Kind = `'"'`
+		case value == "int64" || value == "float64":
+			variant.Kind = "'0'"
+		case value == "bool":
+			variant.Kind = "'t'"
+		case isInputFileType(element) || isChatIdType(element) || types.IsPolymorphic(element):
+			continue
+		case isArrayType(value):
+			variant.Kind = "'['"
+		default:
+			variant.Kind = "'{'"
+		}
+
+		if t, ok := types[element]; ok {
+			shape := getObjectShape(t)
+			variant.Shape = &shape
+		}
+
+		variants = append(variants, variant)
+	}
+
+	// Shapes are matched from the smallest one like the subtypes of polymorphic types
+	sort.SliceStable(variants, func(i, j int) bool {
+		return getVariantShapeSize(variants[i]) < getVariantShapeSize(variants[j])
+	})
+
+	return
+}
+
+func getVariantShapeSize(variant RequestDecodedVariantTemplateData) int {
+	if variant.Shape == nil {
+		return 0
+	}
+
+	return len(variant.Shape.Known)
+}
+
+// buildRegistryDecodeTests returns a payload for every variant and subtype of the decoded request
+// fields. Payloads with files are skipped because input files are never decoded from JSON.
+func buildRegistryDecodeTests(types Types, requests []RequestTemplateData) (tests []RegistryDecodeTestTemplateData) {
+	tests = make([]RegistryDecodeTestTemplateData, 0)
+	for _, request := range requests {
+		required := make(map[string]string)
+		hasFiles := false
+		for _, field := range request.Fields {
+			if field.Field.IsRequired {
+				required[field.Field.Key] = getSampleJson(types, field.Field.Type, 1)
+				hasFiles = hasFiles || hasInputFiles(types, strings.Split(field.Field.Type, " or ")[0])
+			}
+		}
+
+		if hasFiles {
+			continue
+		}
+
+		for _, field := range request.DecodedFields {
+			test := RegistryDecodeTestTemplateData{
+				Method: request.Method.Key,
+				Field:  field.Name,
+				Key:    field.Key,
+				IsItem: field.IsArray,
+			}
+
+			for _, sample := range getRequestDecodedSamples(types, request.Method.Fields[field.Key], field) {
+				payload := maps.Clone(required)
+				payload[field.Key] = sample.Json
+
+				test.Name = request.Method.Key + " " + field.Key + " " + sample.Type
+				test.Payload = encodeSampleFields(payload)
+				test.Type = sample.Type
+				tests = append(tests, test)
+			}
+		}
+	}
+
+	return
+}
+
+func getRequestDecodedSamples(types Types, field *Field, decoded RequestDecodedFieldTemplateData) (samples []InterfaceSampleTemplateData) {
+	samples = make([]InterfaceSampleTemplateData, 0)
+	if decoded.Decoder != "" {
+		items, _ := getInterfaceSamples(types, buildInterfaceTemplateData(types, types[strings.TrimPrefix(field.Type, "[]")]))
+		for _, sample := range items {
+			if decoded.IsArray {
+				sample.Json = "[" + sample.Json + "]"
+			}
+
+			sample.Type = "*telegram." + sample.Type
+			samples = append(samples, sample)
+		}
+
+		return
+	}
+
+	shapes := make(map[string][]InterfaceShapeTemplateData)
+	for _, variant := range decoded.Variants {
+		if variant.Shape != nil {
+			shapes[variant.Kind] = append(shapes[variant.Kind], *variant.Shape)
+		}
+	}
+
+	for _, variant := range decoded.Variants {
+		element := strings.TrimPrefix(variant.ApiType, "[]")
+		if hasInputFiles(types, element) {
+			continue
+		}
+
+		sample := getSampleJson(types, element, 1)
+		if variant.Shape != nil {
+			var ok bool
+			if sample, ok = getInterfaceSample(types, InterfaceTemplateData{Shapes: shapes[variant.Kind]}, element); !ok {
+				continue
+			}
+		}
+
+		if isArrayType(variant.ApiType) {
+			sample = "[" + sample + "]"
+		}
+
+		samples = append(samples, InterfaceSampleTemplateData{Type: variant.Type, Json: sample})
 	}
 
+	return
+}
+
+func buildJsonTestsTemplateData(data RequestTemplateData) (tests JsonTestsTemplateData) {
+	tests.Fields = make([]JsonTestFieldTemplateData, 0, len(data.Fields))
+	tests.Omitted = make([]string, 0)
+	for _, field := range data.Fields {
+		if !field.Field.IsRequired {
+			tests.Omitted = append(tests.Omitted, field.Field.Key)
+		}
+
+		if value, json, ok := getJsonTestValue(field); ok {
+			tests.Fields = append(tests.Fields, JsonTestFieldTemplateData{
+				Name:  field.Name,
+				Key:   field.Field.Key,
+				Value: value,
+				Json:  json,
+			})
+
+			tests.IsTelegramUsed = tests.IsTelegramUsed || strings.Contains(value, "telegram.")
+		}
+	}
+
+	return
+}
+
+func getJsonTestValue(field RequestFieldTemplateData) (value string, json string, ok bool) {
+	if len(field.Variants) > 0 || field.IsArray {
+		return
+	}
+
+	switch {
+	case field.Enum != "":
+		value, json = field.Enum+strcase.ToCamel(field.Field.Enum[0]), strconv.Quote(field.Field.Enum[0])
+	case field.Field.Type == "string":
+		value = strconv.Quote("test_" + field.Field.Key)
+		json = value
+	case field.Field.Type == "int64":
+		value, json = "int64(123)", "123"
+	case field.Field.Type == "float64":
+		value, json = "123.45", "123.45"
+	case field.Field.Type == "bool":
+		value, json = "true", "true"
+	case field.IsChatId:
+		value, json = `telegram.NewChatId(123456, "")`, "123456"
+	case field.IsInputFile:
+		value, json = `telegram.NewInputFile("file_id_123", nil, "")`, `"file_id_123"`
+	default:
+		return
+	}
+
+	if !field.Field.IsRequired {
+		value = "ptr(" + value + ")"
+	}
+
+	return value, json, true
+}
+
+func buildValidateTestsTemplateData(types Types, data RequestTemplateData) (tests ValidateTestsTemplateData) {
+	valid := make([]ValidateCaseFieldTemplateData, 0, len(data.Fields))
+	invalid := make([]ValidateCaseTemplateData, 0)
+	empty := ""
+	for _, field := range data.Fields {
+		value, ok := getValidateTestValue(types, field, true)
+		if !ok {
+			continue
+		}
+
+		valid = append(valid, ValidateCaseFieldTemplateData{Name: field.Name, Value: value})
+
+		if empty == "" && field.Field.IsRequired && (field.Field.Type == "string" || field.IsChatId || field.IsInterface || field.Field.Constraint != nil && field.Field.Constraint.Min > 0) {
+			empty = field.Field.Key
+		}
+
+		if value, ok = getValidateTestValue(types, field, false); ok {
+			invalid = append(invalid, ValidateCaseTemplateData{
+				Name:   "invalid " + field.Field.Key,
+				Fields: []ValidateCaseFieldTemplateData{{Name: field.Name, Value: value}},
+				Error:  field.Field.Key,
+			})
+		}
+
+		c := field.Field.Constraint
+		if c == nil || c.Kind != ConstraintLength {
+			continue
+		}
+
+		long := ValidateCaseFieldTemplateData{Name: field.Name, Value: value}
+
+		// Telegram counts characters in UTF-16 code units, so every emoji takes two of them
+		value = fmt.Sprintf(`strings.Repeat("\U0001F600", %d)`, c.Max/2+1)
+		if !field.Field.IsRequired {
+			value = "ptr(" + value + ")"
+		}
+
+		invalid = append(invalid, ValidateCaseTemplateData{
+			Name:   "invalid " + field.Field.Key + " in utf-16",
+			Fields: []ValidateCaseFieldTemplateData{{Name: field.Name, Value: value}},
+			Error:  field.Field.Key,
+		})
+
+		// The length of the text with markup is known only after entities parsing
+		if field.Entities != "" {
+			entities := ValidateCaseFieldTemplateData{Name: field.Entities}
+			for _, f := range data.Fields {
+				if f.Name == field.Entities {
+					entities.Value = fmt.Sprintf("make(%s, 1)", f.Type)
+				}
+			}
+
+			invalid = append(invalid, ValidateCaseTemplateData{
+				Name:   "long " + field.Field.Key + " with entities",
+				Fields: []ValidateCaseFieldTemplateData{long, entities},
+			})
+		}
+	}
+
+	tests.Cases = make([]ValidateCaseTemplateData, 0, len(invalid)+2)
+	if !data.IsEmptyValid {
+		tests.Cases = append(tests.Cases, ValidateCaseTemplateData{Name: "empty request", Error: empty})
+	}
+
+	tests.Cases = append(tests.Cases, ValidateCaseTemplateData{Name: "valid request", Fields: valid})
+
+	// Every violating case starts from the valid request and breaks exactly one field
+	for _, item := range invalid {
+		fields := make([]ValidateCaseFieldTemplateData, 0, len(valid)+1)
+		for _, field := range valid {
+			if field.Name == item.Fields[0].Name {
+				field = item.Fields[0]
+			} else if isEntitiesField(data.Fields, item.Fields[0].Name, field.Name) {
+				continue
+			}
+			fields = append(fields, field)
+		}
+
+		item.Fields = append(fields, item.Fields[1:]...)
+		tests.Cases = append(tests.Cases, item)
+	}
+
+	for _, item := range tests.Cases {
+		for _, field := range item.Fields {
+			if strings.Contains(field.Value, "telegram.") {
+				tests.IsTelegramUsed = true
+			}
+		}
+	}
+
+	return
+}
+
+// getEntitiesFields returns names of the optional parse mode and entities fields of the text field,
+// e.g. "parse_mode" and "caption_entities" for "caption", or "question_parse_mode" and "question_entities" for "question".
+func getEntitiesFields(fields Fields, key string) (parseMode, entities string) {
+	for _, candidate := range []string{key + "_parse_mode", "parse_mode"} {
+		if field, ok := fields[candidate]; ok && !field.IsRequired {
+			parseMode = strcase.ToCamel(candidate)
+			break
+		}
+	}
+
+	for _, candidate := range []string{key + "_entities", "entities"} {
+		if field, ok := fields[candidate]; ok && !field.IsRequired && isArrayType(field.Type) {
+			entities = strcase.ToCamel(candidate)
+			break
+		}
+	}
+
+	return
+}
+
+// isEntitiesField reports whether the field is the parse mode or entities field of the text field.
+func isEntitiesField(fields []RequestFieldTemplateData, text, name string) bool {
+	for _, field := range fields {
+		if field.Name == text {
+			return name == field.ParseMode || name == field.Entities
+		}
+	}
+
+	return false
+}
+
+func buildConstructorTestsTemplateData(types Types, data RequestTemplateData) (tests ConstructorTestsTemplateData) {
+	tests.Required = make([]ConstructorTestFieldTemplateData, 0)
+	tests.Optional = make([]ConstructorTestFieldTemplateData, 0)
+	for _, field := range data.Fields {
+		item := ConstructorTestFieldTemplateData{
+			Name:      field.Name,
+			Param:     field.Param,
+			Value:     getParamTestValue(types, field),
+			IsPointer: field.Type != field.ParamType && len(field.Variants) == 0,
+		}
+
+		if field.Field.IsRequired {
+			tests.Required = append(tests.Required, item)
+		} else {
+			tests.Optional = append(tests.Optional, item)
+		}
+
+		tests.IsTelegramUsed = tests.IsTelegramUsed || strings.Contains(item.Value, "telegram.")
+	}
+
+	return
+}
+
+// getParamTestValue returns a Go expression of the constructor or setter parameter type of the field.
+func getParamTestValue(types Types, field RequestFieldTemplateData) string {
+	switch {
+	case field.IsInterface:
+		return getInterfaceTestValue(types, field)
+	case field.Enum != "":
+		return field.Enum + strcase.ToCamel(field.Field.Enum[0])
+	case field.IsArray:
+		return fmt.Sprintf("make(%s, 1)", field.ParamType)
+	case field.IsChatId:
+		return `telegram.NewChatId(123456, "")`
+	case field.IsInputFile:
+		return `telegram.NewInputFile("file_id_123", nil, "")`
+	case field.Field.Type == "string":
+		return strconv.Quote("test_" + field.Field.Key)
+	case field.Field.Type == "int64" || field.Field.Type == "float64":
+		return field.Field.Type + "(123)"
+	case field.Field.Type == "bool":
+		return "true"
+	default:
+		return "*new(" + field.ParamType + ")"
+	}
+}
+
+// buildDoTestTemplateData returns a sample result of the method and the type it must be decoded to.
+func buildDoTestTemplateData(types Types, data RequestTemplateData) *DoTestTemplateData {
+	test := &DoTestTemplateData{
+		Json: getSampleJson(types, data.Method.ReturnType, 0),
+		Want: "*new(" + data.ResultType + ")",
+	}
+
+	if elementType := strings.TrimPrefix(data.Method.ReturnType, "[]"); types.IsPolymorphic(elementType) {
+		samples, _ := getInterfaceSamples(types, buildInterfaceTemplateData(types, types[elementType]))
+		if len(samples) == 0 {
+			return nil
+		}
+
+		item := "new(" + getGoType(types, samples[0].Type, true, "telegram") + ")"
+		if data.IsResponseArray {
+			test.Json = "[" + samples[0].Json + "]"
+			test.Item = item
+		} else {
+			test.Json = samples[0].Json
+			test.Want = item
+		}
+	}
+
+	test.IsTelegramUsed = strings.Contains(test.Want+test.Item, "telegram.")
+
+	return test
+}
+
+func getValidateTestValue(types Types, field RequestFieldTemplateData, isValid bool) (value string, ok bool) {
+	c := field.Field.Constraint
+
+	switch {
+	case field.Enum != "":
+		if isValid {
+			value = field.Enum + strcase.ToCamel(field.Field.Enum[0])
+		} else {
+			value = field.Enum + `("unsupported")`
+		}
+	case c != nil && (c.Kind == ConstraintLength || c.Kind == ConstraintBytes):
+		n := max(c.Min, 1)
+		if !isValid {
+			n = c.Max + 1
+		}
+		value = fmt.Sprintf(`strings.Repeat("a", %d)`, n)
+	case c != nil && c.Kind == ConstraintRange:
+		n := c.Min
+		if !isValid {
+			n = c.Max + 1
+		}
+		value = strconv.FormatInt(n, 10)
+		if !field.Field.IsRequired {
+			value = fmt.Sprintf("%s(%d)", field.Field.Type, n)
+		}
+	case c != nil && c.Kind == ConstraintItems:
+		n := c.Min
+		if !isValid {
+			n = c.Max + 1
+		}
+		return fmt.Sprintf("make(%s, %d)", field.Type, n), true
+	case !isValid || !field.Field.IsRequired:
+		return
+	case field.Field.Type == "string":
+		return strconv.Quote("test_" + field.Field.Key), true
+	case field.IsChatId:
+		return `telegram.NewChatId(123456, "")`, true
+	case field.IsInterface:
+		return getInterfaceTestValue(types, field), true
+	default:
+		return
+	}
+
+	if !field.Field.IsRequired {
+		value = "ptr(" + value + ")"
+	}
+
+	return value, true
+}
+
+func getInterfaceTestValue(types Types, field RequestFieldTemplateData) string {
+	if len(field.Variants) > 0 {
+		variant := field.Variants[0][0]
+		switch {
+		case variant.Type == "string":
+			return strconv.Quote("test_" + field.Field.Key)
+		case variant.Type == "int64" || variant.Type == "float64":
+			return variant.Type + "(123)"
+		case variant.Type == "bool":
+			return "true"
+		case variant.IsChatId:
+			return `telegram.NewChatId(123456, "")`
+		case variant.IsInputFile:
+			return `telegram.NewInputFile("file_id_123", nil, "")`
+		}
+
+		return variant.Type + "{}"
+	}
+
+	if t, ok := types[field.Field.Type]; ok && len(t.Subtypes) > 0 {
+		return "&telegram." + t.Subtypes[0] + "{}"
+	}
+
+	return strconv.Quote("test_" + field.Field.Key)
+}
+
+func getGoType(types Types, value string, isRequired bool, pkg string) (t string) {
+	hasSubtypes := types.IsPolymorphic(value)
+
 	hasVariants := len(strings.Split(value, " or ")) > 1
 
 	isArray := isArrayType(value) && !hasVariants
 	isObject := isObjectType(value) && !hasVariants
 
-	if isObject && !hasSubtypes {
+	if isObject && hasSubtypes {
+		t = value
+		if pkg != "" {
+			t = pkg + "." + t
+		}
+	} else if isObject {
 		t = value
 		if pkg != "" {
 			t = pkg + "." + t
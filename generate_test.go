@@ -0,0 +1,69 @@
+package main
+
+import "testing"
+
+func TestBuildEnumTemplateData(t *testing.T) {
+	chat := func(key string) *Type {
+		return &Type{
+			Name: "Chat",
+			Fields: Fields{
+				key: {Key: key, Type: "string", IsRequired: true, Enum: []string{"private", "group"}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		types   Types
+		methods Methods
+		want    string
+	}{
+		{
+			name:  "free name",
+			types: Types{"Chat": chat("type")},
+			want:  "ChatType",
+		},
+		{
+			name:  "type name",
+			types: Types{"Chat": chat("type"), "ChatType": {Name: "ChatType"}},
+			want:  "ChatTypeValue",
+		},
+		{
+			name:  "type name with value suffix",
+			types: Types{"Chat": chat("type"), "ChatType": {Name: "ChatType"}, "ChatTypeValue": {Name: "ChatTypeValue"}},
+			want:  "ChatTypeValue2",
+		},
+		{
+			name:  "value constant",
+			types: Types{"Chat": chat("type"), "ChatTypePrivate": {Name: "ChatTypePrivate"}},
+			want:  "ChatTypeValue",
+		},
+		{
+			name:    "union name",
+			types:   Types{"Chat": chat("or_bool")},
+			methods: Methods{"getChat": {Key: "getChat", ReturnType: "Chat or bool"}},
+			want:    "ChatOrBoolValue",
+		},
+		{
+			name: "update type",
+			types: Types{
+				"Update": {
+					Name: "Update",
+					Fields: Fields{
+						"type": {Key: "type", Type: "string", Enum: []string{"message", "poll"}},
+					},
+				},
+			},
+			want: "UpdateTypeValue",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, _ := buildEnumTemplateData(tt.types, tt.methods)
+			if len(items) != 1 || items[0].Name != tt.want {
+				t.Errorf("enums = %+v, want %s", items, tt.want)
+			}
+		})
+	}
+}
@@ -60,6 +60,61 @@ func getNodeText(node *html.Node) (text string) {
 	return strings.TrimRight(text, " ")
 }
 
+func getNodeInlineText(node *html.Node) string {
+	return strings.Join(strings.Fields(getNodeRawText(node)), " ")
+}
+
+// getNodeInlineTextOffset returns the position of target in the inline text of root.
+func getNodeInlineTextOffset(root *html.Node, target *html.Node) int {
+	var prefix strings.Builder
+	var walk func(node *html.Node) bool
+	walk = func(node *html.Node) bool {
+		if node == target {
+			return true
+		}
+
+		if node.Type == html.TextNode {
+			prefix.WriteString(node.Data)
+		}
+
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if walk(child) {
+				return true
+			}
+		}
+
+		return false
+	}
+	walk(root)
+
+	text := strings.Join(strings.Fields(prefix.String()), " ")
+	if text == "" {
+		return 0
+	}
+
+	raw := prefix.String()
+	if strings.TrimRightFunc(raw, unicode.IsSpace) != raw || strings.TrimLeftFunc(getNodeRawText(target), unicode.IsSpace) != getNodeRawText(target) {
+		return len(text) + 1
+	}
+
+	return len(text)
+}
+
+func getNodeRawText(node *html.Node) string {
+	findOpts := FindOpts{
+		Criteria: func(node *html.Node) bool {
+			return node.Type == html.TextNode
+		},
+	}
+
+	var text strings.Builder
+	for _, n := range findAllNodes(node, &findOpts) {
+		text.WriteString(n.Data)
+	}
+
+	return text.String()
+}
+
 func findNextNode(node *html.Node, opts *FindOpts) *html.Node {
 	opts.MaxResults = 1
 
@@ -134,3 +189,7 @@ func isInputFileType(t string) bool {
 func isChatIdType(t string) bool {
 	return t == ChatIdType
 }
+
+func isUnionType(t string) bool {
+	return strings.Contains(t, " or ")
+}
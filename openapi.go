@@ -0,0 +1,186 @@
+package main
+
+const OpenApiVersion = "3.1.0"
+const OpenApiTitle = "Telegram Bot API"
+const OpenApiServerUrl = "https://api.telegram.org/bot{token}"
+const OpenApiSchemasRef = "#/components/schemas/"
+const OpenApiErrorSchema = "Error"
+
+type OpenApi struct {
+	OpenApi      string                      `json:"openapi"`
+	Info         OpenApiInfo                 `json:"info"`
+	ExternalDocs *OpenApiExternalDocs        `json:"externalDocs,omitempty"`
+	Servers      []*OpenApiServer            `json:"servers"`
+	Paths        map[string]*OpenApiPathItem `json:"paths"`
+	Components   OpenApiComponents           `json:"components"`
+}
+
+type OpenApiInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type OpenApiExternalDocs struct {
+	Url string `json:"url"`
+}
+
+type OpenApiServer struct {
+	Url       string                            `json:"url"`
+	Variables map[string]*OpenApiServerVariable `json:"variables,omitempty"`
+}
+
+type OpenApiServerVariable struct {
+	Default     string `json:"default"`
+	Description string `json:"description,omitempty"`
+}
+
+type OpenApiPathItem struct {
+	Post *OpenApiOperation `json:"post"`
+}
+
+type OpenApiOperation struct {
+	OperationId  string                      `json:"operationId"`
+	Description  string                      `json:"description,omitempty"`
+	ExternalDocs *OpenApiExternalDocs        `json:"externalDocs,omitempty"`
+	RequestBody  *OpenApiRequestBody         `json:"requestBody,omitempty"`
+	Responses    map[string]*OpenApiResponse `json:"responses"`
+}
+
+type OpenApiRequestBody struct {
+	Required bool                         `json:"required"`
+	Content  map[string]*OpenApiMediaType `json:"content"`
+}
+
+type OpenApiResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenApiMediaType `json:"content"`
+}
+
+type OpenApiMediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type OpenApiComponents struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+func buildOpenApi(spec *Spec) *OpenApi {
+	doc := &OpenApi{
+		OpenApi: OpenApiVersion,
+		Info: OpenApiInfo{
+			Title: OpenApiTitle,
+		},
+		ExternalDocs: &OpenApiExternalDocs{
+			Url: TelegramBotsApiUrl,
+		},
+		Servers: []*OpenApiServer{
+			{
+				Url: OpenApiServerUrl,
+				Variables: map[string]*OpenApiServerVariable{
+					"token": {
+						Default:     "",
+						Description: "Bot authentication token",
+					},
+				},
+			},
+		},
+		Paths: make(map[string]*OpenApiPathItem, len(spec.Methods)),
+		Components: OpenApiComponents{
+			Schemas: make(map[string]*Schema, len(spec.Types)+1),
+		},
+	}
+
+	if spec.Version != nil {
+		doc.Info.Version = spec.Version.Number
+	}
+
+	for _, method := range spec.Methods {
+		doc.Paths["/"+method.Key] = &OpenApiPathItem{
+			Post: buildOpenApiOperation(spec.Types, method),
+		}
+	}
+
+	for name, t := range spec.Types {
+		if isInputFileType(name) {
+			continue
+		}
+
+		doc.Components.Schemas[name] = getTypeSchema(spec.Types, t, OpenApiSchemasRef)
+	}
+
+	doc.Components.Schemas[OpenApiErrorSchema] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"ok":          {Type: "boolean", Const: false},
+			"error_code":  {Type: "integer", Format: "int64"},
+			"description": {Type: "string"},
+			"parameters":  {Type: "object"},
+		},
+		Required: []string{"description", "error_code", "ok"},
+	}
+
+	return doc
+}
+
+func buildOpenApiOperation(types Types, method *Method) *OpenApiOperation {
+	operation := &OpenApiOperation{
+		OperationId: method.Key,
+		Description: method.Description,
+		Responses: map[string]*OpenApiResponse{
+			"200": {
+				Description: "Successful response",
+				Content: map[string]*OpenApiMediaType{
+					"application/json": {
+						Schema: &Schema{
+							Type: "object",
+							Properties: map[string]*Schema{
+								"ok":     {Type: "boolean", Const: true},
+								"result": getSchema(types, method.ReturnType, OpenApiSchemasRef),
+							},
+							Required: []string{"ok", "result"},
+						},
+					},
+				},
+			},
+			"default": {
+				Description: "Error response",
+				Content: map[string]*OpenApiMediaType{
+					"application/json": {
+						Schema: &Schema{Ref: OpenApiSchemasRef + OpenApiErrorSchema},
+					},
+				},
+			},
+		},
+	}
+
+	if method.Anchor != "" {
+		operation.ExternalDocs = &OpenApiExternalDocs{
+			Url: getDocumentationLink(method.Anchor),
+		}
+	}
+
+	if len(method.Fields) == 0 {
+		return operation
+	}
+
+	contentType := "application/json"
+	for _, field := range method.Fields {
+		if hasInputFiles(types, field.Type) {
+			contentType = "multipart/form-data"
+			break
+		}
+	}
+
+	schema := getFieldsSchema(types, method.Fields, OpenApiSchemasRef)
+
+	operation.RequestBody = &OpenApiRequestBody{
+		Required: len(schema.Required) > 0,
+		Content: map[string]*OpenApiMediaType{
+			contentType: {
+				Schema: schema,
+			},
+		},
+	}
+
+	return operation
+}
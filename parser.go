@@ -1,12 +1,18 @@
 package main
 
 import (
+	"bytes"
 	"errors"
-	"log"
+	"fmt"
+	"io"
 	"net/http"
+	"os"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"golang.org/x/net/html"
@@ -14,9 +20,40 @@ import (
 
 const TelegramBotsApiUrl = "https://core.telegram.org/bots/api"
 
+const RecentChangesTitle = "Recent changes"
+const RecentChangesAnchor = "recent-changes"
+const ChangesDateLayout = "January 2, 2006"
+
+const ConstraintLength = "length"
+const ConstraintBytes = "bytes"
+const ConstraintRange = "range"
+const ConstraintItems = "items"
+
 const BlockMethods = "methods"
 const BlockTypes = "types"
 
+type Version struct {
+	Number string `json:"number"`
+	Date   string `json:"date"`
+}
+
+type Change struct {
+	Version string        `json:"version"`
+	Date    string        `json:"date"`
+	Items   []*ChangeItem `json:"items"`
+}
+
+type ChangeItem struct {
+	Text  string        `json:"text"`
+	Links []*ChangeLink `json:"links"`
+}
+
+type ChangeLink struct {
+	Text   string `json:"text"`
+	Anchor string `json:"anchor"`
+	Offset int    `json:"offset"`
+}
+
 type Types map[string]*Type
 
 func (t Types) GetFilteredKeys() (keys []string) {
@@ -34,29 +71,135 @@ func (t Types) GetFilteredKeys() (keys []string) {
 	return
 }
 
+func (t Types) GetPolymorphicKeys() (keys []string) {
+	keys = make([]string, 0)
+	for key, value := range t {
+		if len(value.Subtypes) == 0 {
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return
+}
+
+func (t Types) IsPolymorphic(key string) bool {
+	value, ok := t[key]
+	return ok && len(value.Subtypes) > 0
+}
+
+func (t Types) GetDiscriminator(key string) (discriminator string, values map[string]string) {
+	value, ok := t[key]
+	if !ok || len(value.Subtypes) == 0 {
+		return
+	}
+
+	first, ok := t[value.Subtypes[0]]
+	if !ok {
+		return
+	}
+
+	candidates := make([]string, 0)
+	for fieldKey, field := range first.Fields {
+		if field.Const != "" {
+			candidates = append(candidates, fieldKey)
+		}
+	}
+	sort.Strings(candidates)
+
+candidates:
+	for _, candidate := range candidates {
+		values = make(map[string]string, len(value.Subtypes))
+		for _, subtype := range value.Subtypes {
+			st, ok := t[subtype]
+			if !ok {
+				continue candidates
+			}
+
+			field, ok := st.Fields[candidate]
+			if !ok || field.Const == "" {
+				continue candidates
+			}
+
+			if _, ok = values[field.Const]; ok {
+				continue candidates
+			}
+
+			values[field.Const] = subtype
+		}
+
+		discriminator = candidate
+		return
+	}
+
+	values = nil
+
+	return
+}
+
 type Type struct {
-	Name     string
-	Subtypes []string
-	Fields   Fields
+	Name        string   `json:"name"`
+	Anchor      string   `json:"anchor"`
+	Description string   `json:"description"`
+	Subtypes    []string `json:"subtypes"`
+	Fields      Fields   `json:"fields"`
 }
 
 type Methods map[string]*Method
 
+func (m Methods) GetSortedKeys() (keys []string) {
+	keys = make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return
+}
+
 type Method struct {
-	Key        string
-	ReturnType string
-	Fields     Fields
+	Key         string `json:"key"`
+	Anchor      string `json:"anchor"`
+	Description string `json:"description"`
+	ReturnType  string `json:"return_type"`
+	Fields      Fields `json:"fields"`
 }
 
 type Fields map[string]*Field
 
+func (f Fields) GetSortedKeys() (keys []string) {
+	keys = make([]string, 0, len(f))
+	for key := range f {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return
+}
+
 type Field struct {
-	Key        string
-	Type       string
-	IsRequired bool
+	Key         string      `json:"key"`
+	Type        string      `json:"type"`
+	IsRequired  bool        `json:"required"`
+	Const       string      `json:"const,omitempty"`
+	Enum        []string    `json:"enum,omitempty"`
+	Constraint  *Constraint `json:"constraint,omitempty"`
+	Description string      `json:"description"`
 }
 
-func fetch() (doc *html.Node, err error) {
+type Constraint struct {
+	Kind                 string `json:"kind"`
+	Min                  int64  `json:"min"`
+	Max                  int64  `json:"max"`
+	AfterEntitiesParsing bool   `json:"after_entities_parsing,omitempty"`
+}
+
+func fetch(snapshotPath string) (doc *html.Node, err error) {
 	var res *http.Response
 	if res, err = http.Get(TelegramBotsApiUrl); err != nil {
 		return
@@ -64,15 +207,53 @@ func fetch() (doc *html.Node, err error) {
 	//goland:noinspection GoUnhandledErrorResult
 	defer res.Body.Close()
 
-	if doc, err = html.Parse(res.Body); err != nil {
+	if res.StatusCode != http.StatusOK {
+		err = fmt.Errorf("unexpected response status: %s", res.Status)
+		return
+	}
+
+	var body io.Reader = res.Body
+	if snapshotPath != "" {
+		var data []byte
+		if data, err = io.ReadAll(res.Body); err != nil {
+			return
+		}
+
+		if err = os.WriteFile(snapshotPath, data, 0o644); err != nil {
+			return
+		}
+
+		body = bytes.NewReader(data)
+	}
+
+	if doc, err = html.Parse(body); err != nil {
 		return
 	}
 
 	return
 }
 
-func parse(doc *html.Node) (methods Methods, types Types, err error) {
-	// Content
+func load(path string) (doc *html.Node, err error) {
+	var body io.Reader = os.Stdin
+	if path != "-" {
+		var file *os.File
+		if file, err = os.Open(path); err != nil {
+			return
+		}
+		//goland:noinspection GoUnhandledErrorResult
+		defer file.Close()
+
+		body = file
+	}
+
+	if doc, err = html.Parse(body); err != nil {
+		return
+	}
+
+	return
+}
+
+func findContent(doc *html.Node) *html.Node {
 	findContentOpts := FindOpts{
 		Criteria: func(node *html.Node) bool {
 			if node.Type == html.ElementNode && node.Data == "div" {
@@ -84,7 +265,152 @@ func parse(doc *html.Node) (methods Methods, types Types, err error) {
 		},
 	}
 
-	if doc = findNextNode(doc, &findContentOpts); doc == nil {
+	return findNextNode(doc, &findContentOpts)
+}
+
+func parseChanges(doc *html.Node, diags *Diagnostics) (changes []*Change, err error) {
+	if doc = findContent(doc); doc == nil {
+		err = errors.New("content not found")
+		return
+	}
+
+	changes = make([]*Change, 0)
+
+	re := regexp.MustCompile(`^Bot API (\d+(?:\.\d+)*)$`)
+
+	var inSection bool
+	var change *Change
+	for node := doc.FirstChild; node != nil; node = node.NextSibling {
+		if node.Type != html.ElementNode {
+			continue
+		}
+
+		if node.Data == "h3" {
+			if inSection {
+				break
+			}
+
+			inSection = getNodeText(node) == RecentChangesTitle
+			continue
+		}
+
+		if !inSection {
+			continue
+		}
+
+		if node.Data == "h4" {
+			change = nil
+
+			title := getNodeText(node)
+			date, dateErr := time.Parse(ChangesDateLayout, title)
+			if dateErr != nil {
+				location := Location{
+					Section: RecentChangesTitle,
+					Name:    title,
+				}
+
+				findAnchorOpts := FindOpts{
+					Criteria: func(node *html.Node) bool {
+						return node.Type == html.ElementNode && node.Data == "a" && getNodeAttributes(node)["class"] == "anchor"
+					},
+				}
+				if anchor := findNextNode(node, &findAnchorOpts); anchor != nil {
+					location.Anchor = getNodeAttributes(anchor)["name"]
+				}
+
+				diags.Add(location, title, "unexpected date format")
+				continue
+			}
+
+			change = &Change{
+				Date:  date.Format(time.DateOnly),
+				Items: make([]*ChangeItem, 0),
+			}
+			changes = append(changes, change)
+
+			continue
+		}
+
+		if change == nil {
+			continue
+		}
+
+		if node.Data == "p" && change.Version == "" {
+			if match := re.FindStringSubmatch(getNodeText(node)); match != nil {
+				change.Version = match[1]
+			}
+		}
+
+		if node.Data == "ul" {
+			change.Items = append(change.Items, getChangeItems(node)...)
+		}
+	}
+
+	return
+}
+
+func getChangeItems(node *html.Node) (items []*ChangeItem) {
+	items = make([]*ChangeItem, 0)
+
+	findLinksOpts := FindOpts{
+		Criteria: func(node *html.Node) bool {
+			if node.Type == html.ElementNode && node.Data == "a" {
+				return strings.HasPrefix(getNodeAttributes(node)["href"], "#")
+			}
+
+			return false
+		},
+	}
+
+	for item := node.FirstChild; item != nil; item = item.NextSibling {
+		if item.Type != html.ElementNode || item.Data != "li" {
+			continue
+		}
+
+		findLinksOpts.ResetCounters()
+
+		links := make([]*ChangeLink, 0)
+		for _, link := range findAllNodes(item, &findLinksOpts) {
+			links = append(links, &ChangeLink{
+				Text:   getNodeInlineText(link),
+				Anchor: strings.TrimPrefix(getNodeAttributes(link)["href"], "#"),
+				Offset: getNodeInlineTextOffset(item, link),
+			})
+		}
+
+		items = append(items, &ChangeItem{
+			Text:  getNodeInlineText(item),
+			Links: links,
+		})
+	}
+
+	return
+}
+
+func getLatestVersion(changes []*Change, diags *Diagnostics) (version *Version) {
+	for _, change := range changes {
+		if change.Version != "" {
+			version = &Version{
+				Number: change.Version,
+				Date:   change.Date,
+			}
+
+			return
+		}
+	}
+
+	location := Location{
+		Section: RecentChangesTitle,
+		Anchor:  RecentChangesAnchor,
+	}
+	diags.Add(location, "", "api version not found")
+
+	return
+}
+
+func parse(doc *html.Node, diags *Diagnostics) (methods Methods, types Types, err error) {
+	// Content
+	if doc = findContent(doc); doc == nil {
 		err = errors.New("content not found")
 		return
 	}
@@ -105,34 +431,60 @@ func parse(doc *html.Node) (methods Methods, types Types, err error) {
 	}
 
 	var currentBlock, currentName string
+	var location Location
 	var desc string
+
+	finishBlock := func() {
+		if currentBlock == BlockMethods {
+			if m, ok := methods[currentName]; ok && m.ReturnType == "" {
+				diags.Add(location, desc, "return type not found")
+			}
+		}
+
+		currentBlock = ""
+		currentName = ""
+	}
+
 	for node := doc.FirstChild; node != nil; node = node.NextSibling {
 		if node.Type != html.ElementNode {
 			continue
 		}
 
 		if node.Data == "h3" || node.Data == "hr" {
-			currentBlock = ""
-			currentName = ""
+			finishBlock()
+
+			if node.Data == "h3" {
+				location = Location{
+					Section: getNodeText(node),
+				}
+			}
 		}
 
 		if node.Data == "h4" {
+			finishBlock()
+
 			findAnchor.ResetCounters()
 			anchor := findNextNode(node, &findAnchor)
 			if anchor == nil {
-				currentBlock = ""
-				currentName = ""
+				continue
+			}
+
+			location.Name = getNodeText(node)
+			location.Anchor = getNodeAttributes(anchor)["name"]
 
+			if len(location.Name) == 0 {
+				diags.Add(location, "", "empty block name")
 				continue
 			}
 
-			currentName = getNodeText(node)
+			currentName = location.Name
 
 			if unicode.IsUpper(rune(currentName[0])) {
 				currentBlock = BlockTypes
 
 				types[currentName] = &Type{
 					Name:     currentName,
+					Anchor:   location.Anchor,
 					Subtypes: make([]string, 0),
 					Fields:   make(Fields),
 				}
@@ -142,6 +494,7 @@ func parse(doc *html.Node) (methods Methods, types Types, err error) {
 
 				methods[currentName] = &Method{
 					Key:    currentName,
+					Anchor: location.Anchor,
 					Fields: make(Fields),
 				}
 			}
@@ -151,10 +504,20 @@ func parse(doc *html.Node) (methods Methods, types Types, err error) {
 			continue
 		}
 
-		if node.Data == "p" && currentBlock == BlockMethods {
-			desc += getBlockDescription(node)
-			if m, ok := methods[currentName]; ok && len(desc) > 0 {
-				m.ReturnType = getMethodReturnType(desc)
+		if node.Data == "p" {
+			switch currentBlock {
+			case BlockMethods:
+				desc += getBlockDescription(node)
+				if m, ok := methods[currentName]; ok {
+					m.Description = appendParagraph(m.Description, getNodeInlineText(node))
+					if len(desc) > 0 && m.ReturnType == "" {
+						m.ReturnType = getMethodReturnType(desc)
+					}
+				}
+			case BlockTypes:
+				if t, ok := types[currentName]; ok {
+					t.Description = appendParagraph(t.Description, getNodeInlineText(node))
+				}
 			}
 		}
 
@@ -162,11 +525,11 @@ func parse(doc *html.Node) (methods Methods, types Types, err error) {
 			switch currentBlock {
 			case BlockMethods:
 				if m, ok := methods[currentName]; ok {
-					m.Fields = getBlockFields(node, currentBlock)
+					m.Fields = getBlockFields(node, currentBlock, location, diags)
 				}
 			case BlockTypes:
 				if t, ok := types[currentName]; ok {
-					t.Fields = getBlockFields(node, currentBlock)
+					t.Fields = getBlockFields(node, currentBlock, location, diags)
 				}
 			}
 		}
@@ -178,6 +541,8 @@ func parse(doc *html.Node) (methods Methods, types Types, err error) {
 		}
 	}
 
+	finishBlock()
+
 	return
 }
 
@@ -185,7 +550,15 @@ func getBlockDescription(node *html.Node) string {
 	return getNodeText(node)
 }
 
-func getBlockFields(node *html.Node, currentBlock string) (fields Fields) {
+func appendParagraph(text, paragraph string) string {
+	if text == "" || paragraph == "" {
+		return text + paragraph
+	}
+
+	return text + "\n\n" + paragraph
+}
+
+func getBlockFields(node *html.Node, currentBlock string, location Location, diags *Diagnostics) (fields Fields) {
 	fields = make(Fields)
 
 	findBodyOpts := FindOpts{
@@ -242,11 +615,20 @@ func getBlockFields(node *html.Node, currentBlock string) (fields Fields) {
 				fieldType = correctType(getNodeText(tableCols[1]))
 			}
 
-			fields[key] = &Field{
-				Key:        key,
-				Type:       fieldType,
-				IsRequired: getNodeText(tableCols[2]) == "Yes",
+			field := &Field{
+				Key:         key,
+				Type:        fieldType,
+				IsRequired:  getNodeText(tableCols[2]) == "Yes",
+				Description: getNodeInlineText(tableCols[3]),
+			}
+
+			if field.Type == "string" {
+				field.Enum = getFieldEnum(field.Description)
 			}
+
+			field.Constraint = getFieldConstraint(field.Type, field.Description)
+
+			fields[key] = field
 		} else if currentBlock == BlockTypes && len(tableCols) == 3 {
 			key := getNodeText(tableCols[0])
 			desc := getNodeText(tableCols[2])
@@ -258,14 +640,125 @@ func getBlockFields(node *html.Node, currentBlock string) (fields Fields) {
 				fieldType = correctType(getNodeText(tableCols[1]))
 			}
 
-			fields[key] = &Field{
-				Key:        key,
-				Type:       fieldType,
-				IsRequired: !strings.HasPrefix(desc, "Optional"),
+			field := &Field{
+				Key:         key,
+				Type:        fieldType,
+				IsRequired:  !strings.HasPrefix(desc, "Optional"),
+				Description: getNodeInlineText(tableCols[2]),
+			}
+
+			if field.IsRequired && field.Type == "string" {
+				field.Const = getFieldConst(field.Description)
+			}
+
+			if field.Type == "string" && field.Const == "" {
+				field.Enum = getFieldEnum(field.Description)
 			}
+
+			field.Constraint = getFieldConstraint(field.Type, field.Description)
+
+			fields[key] = field
 		} else {
-			log.Fatalln("Unexpected number of columns at fields table")
+			diags.Add(location, getNodeText(row), fmt.Sprintf("unexpected number of columns at fields table: %d", len(tableCols)))
+		}
+	}
+
+	return
+}
+
+func getFieldConst(desc string) string {
+	re := regexp.MustCompile(`, (?:must be|always) “?([a-z0-9_]+)”?$`)
+	if match := re.FindStringSubmatch(desc); match != nil {
+		return match[1]
+	}
+
+	return ""
+}
+
+func getFieldConstraint(fieldType, desc string) (constraint *Constraint) {
+	var re *regexp.Regexp
+	var kind string
+	switch {
+	case fieldType == "string":
+		if strings.Contains(desc, " bytes") {
+			re = regexp.MustCompile(`\b(\d+)-(\d+) bytes\b`)
+			kind = ConstraintBytes
+		} else {
+			re = regexp.MustCompile(`\b(\d+)-(\d+) characters\b( after entities parsing)?`)
+			kind = ConstraintLength
+		}
+	case fieldType == "int64" || fieldType == "float64":
+		re = regexp.MustCompile(`(?:\b[Vv]alues between|[,;]) (\d+)-(\d+)(?: are accepted)?(?:[.,;]|$)`)
+		kind = ConstraintRange
+	case isArrayType(fieldType) && !isUnionType(fieldType):
+		re = regexp.MustCompile(`\b(\d+)-(\d+) items\b`)
+		kind = ConstraintItems
+	default:
+		return
+	}
+
+	match := re.FindStringSubmatch(desc)
+	if match == nil {
+		return
+	}
+
+	bounds := make([]int64, 0, 2)
+	for _, value := range match[1:] {
+		if value == "" || !unicode.IsDigit(rune(value[0])) {
+			continue
+		}
+
+		bound, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return
 		}
+
+		bounds = append(bounds, bound)
+	}
+
+	if len(bounds) != 2 || bounds[0] > bounds[1] {
+		return
+	}
+
+	constraint = &Constraint{
+		Kind:                 kind,
+		Min:                  bounds[0],
+		Max:                  bounds[1],
+		AfterEntitiesParsing: strings.HasSuffix(match[0], " after entities parsing"),
+	}
+
+	return
+}
+
+func getFieldEnum(desc string) (values []string) {
+	reStart := regexp.MustCompile(`\b(?:can be|one of|either|pass)\b`)
+	loc := reStart.FindStringIndex(desc)
+	if loc == nil {
+		return
+	}
+
+	desc = desc[loc[1]:]
+
+	reEnd := regexp.MustCompile(`\.\s+[A-Z]`)
+	if end := reEnd.FindStringIndex(desc); end != nil {
+		desc = desc[:end[0]]
+	}
+
+	reQuoted := regexp.MustCompile(`“([^”]*)”`)
+	reValue := regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+	for _, match := range reQuoted.FindAllStringSubmatch(desc, -1) {
+		if !reValue.MatchString(match[1]) {
+			return nil
+		}
+
+		if !slices.Contains(values, match[1]) {
+			values = append(values, match[1])
+		}
+	}
+
+	if len(values) < 2 {
+		return nil
 	}
 
 	return
@@ -290,9 +783,21 @@ func getBlockSubtypes(node *html.Node) (types []string) {
 
 func getMethodReturnType(desc string) (returns string) {
 	re := regexp.MustCompile(`(?:Returns|On success,).*?((?:[Aa]rray of )?[A-Z]\w+)(?: that were sent| of the sent messages?)? (?:object|is returned|on success)`)
-	match := re.FindAllStringSubmatch(desc, -1)
+	match := re.FindStringSubmatchIndex(desc)
+	if match == nil {
+		return
+	}
 
-	return correctType(match[0][1])
+	returns = correctType(desc[match[2]:match[3]])
+
+	reOtherwise := regexp.MustCompile(`^[^.]*?otherwise,? (?:the )?((?:[Aa]rray of )?[A-Z]\w+)(?: object)? is returned`)
+	if otherwise := reOtherwise.FindStringSubmatch(desc[match[1]:]); otherwise != nil {
+		if alternative := correctType(otherwise[1]); alternative != returns {
+			returns += " or " + alternative
+		}
+	}
+
+	return
 }
 
 func correctType(t string) string {
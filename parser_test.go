@@ -0,0 +1,238 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetFieldConstraint(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType string
+		desc      string
+		want      *Constraint
+	}{
+		{
+			name:      "text after entities parsing",
+			fieldType: "string",
+			desc:      "Text of the message to be sent, 1-4096 characters after entities parsing",
+			want:      &Constraint{Kind: ConstraintLength, Min: 1, Max: 4096, AfterEntitiesParsing: true},
+		},
+		{
+			name:      "caption after entities parsing",
+			fieldType: "string",
+			desc:      "Photo caption (may also be used when resending photos by file_id), 0-1024 characters after entities parsing",
+			want:      &Constraint{Kind: ConstraintLength, Min: 0, Max: 1024, AfterEntitiesParsing: true},
+		},
+		{
+			name:      "characters",
+			fieldType: "string",
+			desc:      "Product name, 1-32 characters",
+			want:      &Constraint{Kind: ConstraintLength, Min: 1, Max: 32},
+		},
+		{
+			name:      "bytes",
+			fieldType: "string",
+			desc:      "Bot-defined invoice payload, 1-128 bytes. This will not be displayed to the user, use it for your internal processes.",
+			want:      &Constraint{Kind: ConstraintBytes, Min: 1, Max: 128},
+		},
+		{
+			name:      "values between",
+			fieldType: "int64",
+			desc:      "Limits the number of updates to be retrieved. Values between 1-100 are accepted. Defaults to 100.",
+			want:      &Constraint{Kind: ConstraintRange, Min: 1, Max: 100},
+		},
+		{
+			name:      "range after comma",
+			fieldType: "int64",
+			desc:      "The maximum allowed number of simultaneous HTTPS connections to the webhook for update delivery, 1-100. Defaults to 40.",
+			want:      &Constraint{Kind: ConstraintRange, Min: 1, Max: 100},
+		},
+		{
+			name:      "range after semicolon",
+			fieldType: "int64",
+			desc:      "The maximum number of gifts to be returned; 1-100. Defaults to 100",
+			want:      &Constraint{Kind: ConstraintRange, Min: 1, Max: 100},
+		},
+		{
+			name:      "range at the end",
+			fieldType: "int64",
+			desc:      "The amount of Telegram Stars a user must pay initially and after each subsequent subscription period to be a member of the chat; 1-10000",
+			want:      &Constraint{Kind: ConstraintRange, Min: 1, Max: 10000},
+		},
+		{
+			name:      "items",
+			fieldType: "[]InputPollOption",
+			desc:      "A JSON-serialized list of 2-10 answer options",
+			want:      nil,
+		},
+		{
+			name:      "must include items",
+			fieldType: "[]InputMediaAudio or []InputMediaPhoto",
+			desc:      "A JSON-serialized array describing messages to be sent, must include 2-10 items",
+			want:      nil,
+		},
+		{
+			name:      "array items",
+			fieldType: "[]string",
+			desc:      "A JSON-serialized list of 1-100 items",
+			want:      &Constraint{Kind: ConstraintItems, Min: 1, Max: 100},
+		},
+		{
+			name:      "no constraint",
+			fieldType: "int64",
+			desc:      "Timeout in seconds for long polling. Defaults to 0, i.e. usual short polling.",
+			want:      nil,
+		},
+		{
+			name:      "reversed bounds",
+			fieldType: "string",
+			desc:      "New name, 64-1 characters",
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getFieldConstraint(tt.fieldType, tt.desc); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getFieldConstraint() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFieldEnum(t *testing.T) {
+	tests := []struct {
+		name string
+		desc string
+		want []string
+	}{
+		{
+			name: "can be",
+			desc: "Type of the chat, can be either “private”, “group”, “supergroup” or “channel”",
+			want: []string{"private", "group", "supergroup", "channel"},
+		},
+		{
+			name: "one of",
+			desc: "Format of the sticker, must be one of “static”, “animated”, “video”",
+			want: []string{"static", "animated", "video"},
+		},
+		{
+			name: "next sentence is ignored",
+			desc: "Poll type, can be “quiz” or “regular”, defaults to “regular”. Use “quiz” for quizzes.",
+			want: []string{"quiz", "regular"},
+		},
+		{
+			name: "emoji values",
+			desc: "Emoji on which the dice throw animation is based. Currently, must be one of “🎲”, “🎯”, “🏀”, “⚽”, “🎳”, or “🎰”.",
+			want: nil,
+		},
+		{
+			name: "single value",
+			desc: "Type of the result, must be “article”",
+			want: nil,
+		},
+		{
+			name: "no values",
+			desc: "Unique identifier for the target chat",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getFieldEnum(tt.desc); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getFieldEnum() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFieldConst(t *testing.T) {
+	tests := []struct {
+		name string
+		desc string
+		want string
+	}{
+		{
+			name: "must be",
+			desc: "Type of the result, must be article",
+			want: "article",
+		},
+		{
+			name: "always quoted",
+			desc: "Scope type, must be “default”",
+			want: "default",
+		},
+		{
+			name: "always",
+			desc: "Type of the reaction, always “emoji”",
+			want: "emoji",
+		},
+		{
+			name: "enum",
+			desc: "Type of the chat, can be either “private”, “group”, “supergroup” or “channel”",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getFieldConst(tt.desc); got != tt.want {
+				t.Errorf("getFieldConst() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetMethodReturnType(t *testing.T) {
+	tests := []struct {
+		name string
+		desc string
+		want string
+	}{
+		{
+			name: "sent message",
+			desc: "Use this method to send text messages. On success, the sent Message is returned.",
+			want: "Message",
+		},
+		{
+			name: "object",
+			desc: "A simple method for testing your bot's authentication token. Requires no parameters. Returns basic information about the bot in form of a User object.",
+			want: "User",
+		},
+		{
+			name: "true",
+			desc: "Use this method to change the list of the bot's commands. Returns True on success.",
+			want: "bool",
+		},
+		{
+			name: "array",
+			desc: "Use this method to receive incoming updates using long polling. Returns an Array of Update objects.",
+			want: "[]Update",
+		},
+		{
+			name: "array of sent messages",
+			desc: "Use this method to send a group of photos, videos, documents or audios as an album. On success, an array of Messages that were sent is returned.",
+			want: "[]Message",
+		},
+		{
+			name: "otherwise",
+			desc: "Use this method to edit text and game messages. On success, if the edited message is not an inline message, the edited Message is returned, otherwise True is returned.",
+			want: "Message or bool",
+		},
+		{
+			name: "not found",
+			desc: "Use this method to do something.",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getMethodReturnType(tt.desc); got != tt.want {
+				t.Errorf("getMethodReturnType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const JsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+const JsonSchemaDefsRef = "#/$defs/"
+
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	Id          string             `json:"$id,omitempty"`
+	Ref         string             `json:"$ref,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Const       interface{}        `json:"const,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	OneOf       []*Schema          `json:"oneOf,omitempty"`
+	AnyOf       []*Schema          `json:"anyOf,omitempty"`
+	Defs        map[string]*Schema `json:"$defs,omitempty"`
+}
+
+func writeJsonSchemas(dir string, types Types) (err error) {
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return
+	}
+
+	for name := range types {
+		if isInputFileType(name) {
+			continue
+		}
+
+		if err = writeJson(filepath.Join(dir, name+".json"), buildJsonSchema(types, name)); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func buildJsonSchema(types Types, name string) (schema *Schema) {
+	schema = getTypeSchema(types, types[name], JsonSchemaDefsRef)
+	schema.Schema = JsonSchemaDraft
+	schema.Id = name + ".json"
+	schema.Title = name
+
+	defs := make(map[string]*Schema)
+	collectSchemaDefs(types, schema, name, defs)
+	if len(defs) > 0 {
+		schema.Defs = defs
+	}
+
+	return
+}
+
+func collectSchemaDefs(types Types, schema *Schema, root string, defs map[string]*Schema) {
+	if schema == nil {
+		return
+	}
+
+	if name, ok := strings.CutPrefix(schema.Ref, JsonSchemaDefsRef); ok {
+		if name == root {
+			schema.Ref = "#"
+		} else if _, ok = defs[name]; !ok {
+			if t, ok := types[name]; ok {
+				defs[name] = getTypeSchema(types, t, JsonSchemaDefsRef)
+				collectSchemaDefs(types, defs[name], root, defs)
+			}
+		}
+	}
+
+	collectSchemaDefs(types, schema.Items, root, defs)
+
+	for _, property := range schema.Properties {
+		collectSchemaDefs(types, property, root, defs)
+	}
+
+	for _, variant := range schema.OneOf {
+		collectSchemaDefs(types, variant, root, defs)
+	}
+
+	for _, variant := range schema.AnyOf {
+		collectSchemaDefs(types, variant, root, defs)
+	}
+}
+
+func getTypeSchema(types Types, t *Type, refPrefix string) (schema *Schema) {
+	if len(t.Subtypes) > 0 {
+		variants := make([]*Schema, 0, len(t.Subtypes))
+		for _, subtype := range t.Subtypes {
+			variants = append(variants, getSchema(types, subtype, refPrefix))
+		}
+
+		// Without a discriminator const the subtypes may overlap, so a value can match several of them
+		if discriminator, _ := types.GetDiscriminator(t.Name); discriminator != "" {
+			schema = &Schema{OneOf: variants}
+		} else {
+			schema = &Schema{AnyOf: variants}
+		}
+	} else {
+		schema = getFieldsSchema(types, t.Fields, refPrefix)
+	}
+
+	schema.Description = t.Description
+
+	return
+}
+
+func getFieldsSchema(types Types, fields Fields, refPrefix string) *Schema {
+	schema := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema, len(fields)),
+	}
+
+	for _, field := range fields {
+		schema.Properties[field.Key] = getSchema(types, field.Type, refPrefix)
+		schema.Properties[field.Key].Description = field.Description
+		if field.Const != "" {
+			schema.Properties[field.Key].Const = field.Const
+		}
+		if len(field.Enum) > 0 {
+			schema.Properties[field.Key].Enum = field.Enum
+		}
+		if field.IsRequired {
+			schema.Required = append(schema.Required, field.Key)
+		}
+	}
+	sort.Strings(schema.Required)
+
+	return schema
+}
+
+func getSchema(types Types, value string, refPrefix string) *Schema {
+	if variants := strings.Split(value, " or "); len(variants) > 1 {
+		schemas := make([]*Schema, 0, len(variants))
+		for _, variant := range variants {
+			schemas = append(schemas, getSchema(types, variant, refPrefix))
+		}
+
+		// oneOf requires exactly one match, so it is used only when the variants have different JSON types.
+		// Variants of the same JSON type may overlap: "InputFile or String" are both strings, and objects
+		// like InlineKeyboardMarkup and ReplyKeyboardRemove allow additional properties.
+		if hasDistinctKinds(variants) {
+			return &Schema{OneOf: schemas}
+		}
+
+		return &Schema{AnyOf: schemas}
+	}
+
+	if isArrayType(value) {
+		return &Schema{
+			Type:  "array",
+			Items: getSchema(types, value[2:], refPrefix), // len("[]") == 2
+		}
+	}
+
+	switch value {
+	case "string":
+		return &Schema{Type: "string"}
+	case "int64":
+		return &Schema{Type: "integer", Format: "int64"}
+	case "float64":
+		return &Schema{Type: "number", Format: "double"}
+	case "bool":
+		return &Schema{Type: "boolean"}
+	case ChatIdType:
+		return &Schema{
+			OneOf: []*Schema{
+				{Type: "integer", Format: "int64"},
+				{Type: "string"},
+			},
+		}
+	}
+
+	if isInputFileType(value) {
+		return getInputFileSchema()
+	}
+
+	return &Schema{Ref: refPrefix + value}
+}
+
+// hasDistinctKinds reports whether no two variants can be encoded as values of the same JSON type.
+func hasDistinctKinds(variants []string) bool {
+	kinds := make(map[string]bool, len(variants))
+	for _, variant := range variants {
+		var kind string
+		switch {
+		case isArrayType(variant):
+			kind = "array"
+		case variant == "string" || isInputFileType(variant):
+			kind = "string"
+		case variant == "int64" || variant == "float64":
+			kind = "number"
+		case variant == "bool":
+			kind = "boolean"
+		case isObjectType(variant) && !isChatIdType(variant):
+			kind = "object"
+		default:
+			return false
+		}
+
+		if kinds[kind] {
+			return false
+		}
+
+		kinds[kind] = true
+	}
+
+	return true
+}
+
+func getInputFileSchema() *Schema {
+	return &Schema{
+		Type:   "string",
+		Format: "binary",
+	}
+}
+
+func hasInputFiles(types Types, value string) bool {
+	for _, variant := range strings.Split(value, " or ") {
+		for isArrayType(variant) {
+			variant = variant[2:] // len("[]") == 2
+		}
+
+		if isInputFileType(variant) {
+			return true
+		}
+
+		t, ok := types[variant]
+		if !ok {
+			continue
+		}
+
+		for _, subtype := range t.Subtypes {
+			if hasInputFiles(types, subtype) {
+				return true
+			}
+		}
+
+		if len(getInputFileFields(t.Fields)) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
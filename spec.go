@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+type Spec struct {
+	Version *Version  `json:"version"`
+	Changes []*Change `json:"changes"`
+	Methods Methods   `json:"methods"`
+	Types   Types     `json:"types"`
+}
+
+func readSpec(path string) (spec *Spec, err error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		var file *os.File
+		if file, err = os.Open(path); err != nil {
+			return
+		}
+		//goland:noinspection GoUnhandledErrorResult
+		defer file.Close()
+
+		r = file
+	}
+
+	spec = new(Spec)
+	if err = json.NewDecoder(r).Decode(spec); err != nil {
+		return
+	}
+
+	diags := make(Diagnostics, 0)
+	checkSpec(spec, &diags)
+
+	if err = diags.Err(); err != nil {
+		return
+	}
+
+	return
+}
+
+// checkSpec reports missing blocks and references to types that the spec does not describe, so that a
+// hand-edited spec fails with diagnostics instead of a panic during generation.
+func checkSpec(spec *Spec, diags *Diagnostics) {
+	if spec.Types == nil {
+		diags.Add(Location{Section: BlockTypes}, "", "types not found")
+	}
+
+	if spec.Methods == nil {
+		diags.Add(Location{Section: BlockMethods}, "", "methods not found")
+	}
+
+	if len(*diags) > 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(spec.Types))
+	for key := range spec.Types {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		location := Location{Section: BlockTypes, Name: key}
+
+		t := spec.Types[key]
+		if t == nil {
+			diags.Add(location, "", "type is empty")
+			continue
+		}
+
+		location.Anchor = t.Anchor
+
+		for _, subtype := range t.Subtypes {
+			if spec.Types[subtype] == nil {
+				diags.Add(location, subtype, "subtype not found")
+			}
+		}
+
+		checkSpecFields(spec.Types, t.Fields, location, diags)
+	}
+
+	for _, key := range spec.Methods.GetSortedKeys() {
+		location := Location{Section: BlockMethods, Name: key}
+
+		method := spec.Methods[key]
+		if method == nil {
+			diags.Add(location, "", "method is empty")
+			continue
+		}
+
+		location.Anchor = method.Anchor
+
+		if method.ReturnType == "" {
+			diags.Add(location, "", "return type not found")
+		}
+
+		for _, missing := range getMissingTypes(spec.Types, method.ReturnType) {
+			diags.Add(location, missing, "return type not found")
+		}
+
+		checkSpecFields(spec.Types, method.Fields, location, diags)
+	}
+}
+
+func checkSpecFields(types Types, fields Fields, location Location, diags *Diagnostics) {
+	for _, key := range fields.GetSortedKeys() {
+		field := fields[key]
+		if field == nil || field.Type == "" {
+			diags.Add(location, key, "field type not found")
+			continue
+		}
+
+		for _, missing := range getMissingTypes(types, field.Type) {
+			diags.Add(location, key, "field type not found: "+missing)
+		}
+	}
+}
+
+// getMissingTypes returns the object types referenced by the type value that are not described.
+func getMissingTypes(types Types, value string) (missing []string) {
+	for _, item := range strings.Split(value, " or ") {
+		item = strings.TrimLeft(item, "[]")
+		if item == ChatIdType || !isObjectType(item) {
+			continue
+		}
+
+		if types[item] == nil {
+			missing = append(missing, item)
+		}
+	}
+
+	return
+}
+
+func writeSpec(path string, spec *Spec) error {
+	return writeJson(path, spec)
+}
+
+func writeJson(path string, v interface{}) (err error) {
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		var file *os.File
+		if file, err = os.Create(path); err != nil {
+			return
+		}
+		defer func() {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+
+		w = file
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+
+	if err = encoder.Encode(v); err != nil {
+		return
+	}
+
+	return
+}